@@ -15,17 +15,18 @@
 package main
 
 import (
-	"bufio"
-	"bytes"
-	"encoding/json"
 	"fmt"
 	"io"
 	"log"
 	"os"
 	"runtime"
+	"strconv"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/abc-inc/gutenfmt/gfmt"
+	"github.com/abc-inc/gutenfmt/input"
 	"github.com/alecthomas/chroma/v2"
 	"github.com/alecthomas/chroma/v2/formatters"
 	"github.com/alecthomas/chroma/v2/lexers"
@@ -36,25 +37,41 @@ import (
 	"github.com/spf13/pflag"
 )
 
+var formatsCmd = &cobra.Command{
+	Use:   "formats",
+	Short: "Lists the supported output formats.",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		type format struct {
+			Name        string `json:"NAME"`
+			Description string `json:"DESCRIPTION"`
+			Pretty      bool   `json:"PRETTY"`
+			Style       bool   `json:"THEME"`
+			Extensions  string `json:"EXTENSIONS"`
+		}
+
+		var fs []format
+		for _, f := range gfmt.DefaultRegistry.Formats() {
+			fs = append(fs, format{f.Name, f.Description, f.Pretty, f.Style, strings.Join(f.Extensions, " ")})
+		}
+		if _, err := gfmt.NewTab(os.Stdout).Write(fs); err != nil {
+			log.Fatalln("Cannot write output:", err)
+		}
+	},
+}
+
+// exitStatus is the exit status of the command e.g., set by --exit-status.
+var exitStatus int
+
 var rootCmd = &cobra.Command{
 	Use:   "gutenfmt",
 	Short: "Formats the input as CSV, JSON, YAML, ASCII table, or name and value pairs.",
-	Long: `The gutenfmt utility formats its input to various output formats.
-
-Supported input formats:
-- JSON
-- Name and value pairs, separated by equal sign or colon.
-- Tab-separated name and value pairs
-
-The following output formats are supported:
-- csv: Comma-separated values.
-- json: JSON string. This setting is the default. Optionally, use --pretty.
-- table: ASCII table.
-- text: Name and value pairs, separated by equal sign.
-- tsv: Tab-separated name and value pairs (useful for grep, sed, or awk).
-- yaml: YAML, a machine-readable alternative to JSON. Optionally, use --pretty.
-`,
-	Args: cobra.MaximumNArgs(1),
+	Args: func(cmd *cobra.Command, args []string) error {
+		if positional(cmd.Flags()) {
+			return nil
+		}
+		return cobra.MaximumNArgs(1)(cmd, args)
+	},
 	Run: func(cmd *cobra.Command, args []string) {
 		ff, err := cmd.Flags().GetString("output")
 		if err != nil {
@@ -80,84 +97,210 @@ The following output formats are supported:
 			return
 		}
 
-		if (isatty.IsTerminal(os.Stdin.Fd()) && len(args) == 0) ||
-			(!cmd.Flags().Changed("jq") && (cmd.Flags().Changed("arg") || cmd.Flags().Changed("argjson"))) {
+		// With --args or --jsonargs, the arguments are passed to the jq filter and the input is read from stdin.
+		name, posArgs := append(args, "-")[0], []string(nil)
+		if positional(cmd.Flags()) {
+			name, posArgs = "-", args
+		}
+		nullIn, _ := cmd.Flags().GetBool("null-input")
+		if (isatty.IsTerminal(os.Stdin.Fd()) && name == "-" && !nullIn) ||
+			(!cmd.Flags().Changed("jq") && jqOnly(cmd.Flags())) {
 			_ = cmd.Help()
 			os.Exit(1)
 		}
 
-		m := parse(append(args, "-")[0])
+		if err := configureCSV(cmd.Flags()); err != nil {
+			log.Fatalln(err)
+		}
+		var m any
+		if !nullIn || name != "-" || !isatty.IsTerminal(os.Stdin.Fd()) {
+			in, _ := cmd.Flags().GetString("input")
+			if m, err = parse(name, in); err != nil {
+				log.Fatalln("Cannot read input:", err)
+			}
+		}
+
 		th, _ := cmd.Flags().GetString("theme")
 		p, _ := cmd.Flags().GetString("pretty")
 		p = strings.ToLower(p)
 
-		var w gfmt.Writer
-		switch strings.ToLower(ff) {
-		case "csv":
-			w = gfmt.NewText(os.Stdout)
-			w.(*gfmt.Text).Sep = ","
-		case "":
-			fallthrough
-		case "json":
-			if p == "true" || p == "always" || (p == "auto" && isatty.IsTerminal(os.Stdout.Fd())) {
-				w = gfmt.NewJSON(os.Stdout, gfmt.WithStyle[gfmt.JSON](styles.Get(th)), gfmt.WithPretty[gfmt.JSON]())
-				w.(*gfmt.JSON).Indent = "  "
-			} else {
-				w = gfmt.NewJSON(os.Stdout, gfmt.WithStyle[gfmt.JSON](styles.Get(th)))
+		if ff == "" {
+			ff = "json"
+		}
+		ts, _ := cmd.Flags().GetString("table-style")
+		tStyle, ok := gfmt.LookupTableStyle(ts)
+		if !ok {
+			log.Fatalln("Unknown table style:", ts)
+		}
+
+		cws, _ := cmd.Flags().GetStringSlice("column-width")
+		cols, err := parseColumns(cws)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		cfs, _ := cmd.Flags().GetStringArray("column-format")
+		if cols, err = parseColumnFormats(cfs, cols); err != nil {
+			log.Fatalln(err)
+		}
+
+		vs, _ := cmd.Flags().GetString("vertical")
+		vert, err := gfmt.ParseVertical(vs)
+		if err != nil {
+			log.Fatalln(err)
+		}
+
+		var flat *gfmt.Flatten
+		if f, _ := cmd.Flags().GetBool("flatten"); f {
+			flat = &gfmt.Flatten{}
+			flat.MaxDepth, _ = cmd.Flags().GetInt("flatten-depth")
+			flat.Separator, _ = cmd.Flags().GetString("flatten-separator")
+			switch idx, _ := cmd.Flags().GetString("flatten-index"); idx {
+			case "dot":
+			case "bracket":
+				flat.Brackets = true
+			default:
+				log.Fatalln("Unknown array index style:", idx)
 			}
-		case "table":
-			w = gfmt.NewTab(os.Stdout)
-		case "text":
-			w = gfmt.NewText(os.Stdout)
-			w.(*gfmt.Text).Sep = "="
-		case "tsv":
-			w = gfmt.NewText(os.Stdout)
-			w.(*gfmt.Text).Sep = "\t"
-		case "yaml":
-			if p == "true" || p == "always" || (p == "auto" && isatty.IsTerminal(os.Stdout.Fd())) {
-				w = gfmt.NewYAML(os.Stdout, gfmt.WithStyle[gfmt.YAML](styles.Get(th)), gfmt.WithPretty[gfmt.YAML]())
-			} else {
-				w = gfmt.NewYAML(os.Stdout, gfmt.WithStyle[gfmt.YAML](styles.Get(th)))
+		}
+
+		var hls []gfmt.Highlight
+		rules, _ := cmd.Flags().GetStringArray("highlight")
+		for _, r := range rules {
+			h, err := gfmt.ParseHighlight(r)
+			if err != nil {
+				log.Fatalln(err)
 			}
-		default:
-			_ = cmd.Help()
-			os.Exit(1)
+			hls = append(hls, h)
 		}
 
-		if jq, _ := cmd.Flags().GetString("jq"); jq != "" {
-			var allArgs []gfmt.Arg
-			args, _ := cmd.Flags().GetStringSlice("arg")
-			for _, a := range args {
-				arg, err := gfmt.NewArg(a, true)
-				if err != nil {
-					log.Fatal(err)
+		missing, _ := cmd.Flags().GetString("missing")
+		c := gfmt.Config{
+			Pretty:     p == "true" || p == "always" || (p == "auto" && isatty.IsTerminal(os.Stdout.Fd())),
+			Style:      styles.Get(th),
+			Table:      tStyle,
+			Columns:    cols,
+			Vertical:   vert,
+			Width:      gfmt.TerminalWidth(os.Stdout.Fd()),
+			Flatten:    flat,
+			Missing:    missing,
+			Highlights: hls,
+		}
+		var out io.Writer = os.Stdout
+		var pg *gfmt.Pager
+		if noPager, _ := cmd.Flags().GetBool("no-pager"); !noPager {
+			// Only the output to a terminal is paged, so that piped output is streamed.
+			if p := gfmt.NewPager(os.Stdout); p.Height > 0 {
+				if strings.EqualFold(ff, "table") && vert == gfmt.VerticalOff {
+					p.Header = tStyle.HeaderLines()
 				}
-				allArgs = append(allArgs, *arg)
+				pg, out = p, p
 			}
+		}
 
-			args, _ = cmd.Flags().GetStringSlice("argjson")
-			for _, a := range args {
-				arg, err := gfmt.NewArg(a, false)
-				if err != nil {
-					log.Fatal(err)
-				}
-				allArgs = append(allArgs, *arg)
+		// The output produced before an error is still written.
+		err = write(cmd, out, ff, c, m, nullIn, posArgs)
+		if pg != nil {
+			if cErr := pg.Close(); err == nil {
+				err = cErr
 			}
+		}
+		if err != nil {
+			log.Fatalln(err)
+		}
+	},
+}
 
-			raw, _ := cmd.Flags().GetBool("raw-output")
-			if raw {
-				w = gfmt.NewJQWithArgs(w, jq, allArgs, gfmt.WithRaw())
-			} else {
-				w = gfmt.NewJQWithArgs(w, jq, allArgs)
+// write formats the input m as ff and writes it to out, after applying the
+// field selection, sorting, jq filter or JMESPath query given by flags.
+func write(cmd *cobra.Command, out io.Writer, ff string, c gfmt.Config, m any, nullIn bool, posArgs []string) error {
+	w, err := gfmt.NewWriter(ff, out, c)
+	if err != nil {
+		_ = cmd.Help()
+		os.Exit(1)
+	}
+
+	if f, _ := cmd.Flags().GetString("fields"); f != "" {
+		fs, err := gfmt.ParseFields(f)
+		if err != nil {
+			return err
+		}
+		w = gfmt.NewFields(w, fs...)
+	}
+
+	sortBy, _ := cmd.Flags().GetString("sort")
+	offset, _ := cmd.Flags().GetInt("offset")
+	limit, _ := cmd.Flags().GetInt("limit")
+	if sortBy != "" || offset > 0 || limit > 0 {
+		var ks []gfmt.SortKey
+		if sortBy != "" {
+			if ks, err = gfmt.ParseSortKeys(sortBy); err != nil {
+				return err
 			}
-		} else if q, _ := cmd.Flags().GetString("query"); q != "" {
-			w = gfmt.NewJMESPath(w, q)
 		}
+		w = gfmt.NewRows(w, gfmt.WithSortBy(ks...), gfmt.WithOffset(offset), gfmt.WithLimit(limit))
+	}
 
-		if _, err := w.Write(m); err != nil {
-			log.Fatalln("Cannot write output:", err)
+	if jq, _ := cmd.Flags().GetString("jq"); jq != "" {
+		var allArgs []gfmt.Arg
+		args, _ := cmd.Flags().GetStringSlice("arg")
+		for _, a := range args {
+			arg, err := gfmt.NewArg(a, true)
+			if err != nil {
+				return err
+			}
+			allArgs = append(allArgs, *arg)
 		}
-	},
+
+		args, _ = cmd.Flags().GetStringSlice("argjson")
+		for _, a := range args {
+			arg, err := gfmt.NewArg(a, false)
+			if err != nil {
+				return err
+			}
+			allArgs = append(allArgs, *arg)
+		}
+
+		opts, err := jqOptions(cmd.Flags(), out, posArgs)
+		if err != nil {
+			return err
+		}
+
+		// Like jq, the filter is applied to each document of multiple documents, unless they are slurped.
+		// The results of all documents are written according to --jq-results.
+		var docs []any
+		if s, ok := m.(input.Stream); ok {
+			docs = s
+		} else if m != nil || !nullIn {
+			docs = []any{m}
+		}
+
+		jqw, err := gfmt.NewJQWithArgs(w, jq, allArgs, opts...)
+		if err != nil {
+			return err
+		}
+		if _, err := jqw.WriteInputs(docs); err != nil {
+			return fmt.Errorf("cannot write output: %w", err)
+		}
+		if e, _ := cmd.Flags().GetBool("exit-status"); e {
+			exitStatus = jqw.ExitStatus()
+		}
+		return nil
+	} else if q, _ := cmd.Flags().GetString("query"); q != "" {
+		w = gfmt.NewJMESPath(w, q)
+	}
+
+	if s, ok := m.(input.Stream); ok {
+		// Multiple documents are rendered as rows, unless the query should be applied to each of them.
+		m = []any(s)
+		if each, _ := cmd.Flags().GetBool("per-document"); each {
+			w = gfmt.NewEach(w, out, "\n")
+		}
+	}
+
+	if _, err := w.Write(m); err != nil {
+		return fmt.Errorf("cannot write output: %w", err)
+	}
+	return nil
 }
 
 func main() {
@@ -170,61 +313,318 @@ func main() {
 		theme = "native"
 	}
 
+	rootCmd.Flags().Bool("args", false, "Pass the remaining arguments to the jq filter as positional string arguments ($ARGS.positional) instead of reading them as file.")
 	rootCmd.Flags().StringSlice("arg", nil, "Pass a string value to the jq filter as a predefined variable.")
 	rootCmd.Flags().StringSlice("argjson", nil, "Pass a JSON-encoded value to the jq filter as a predefined variable.")
-	rootCmd.Flags().String("jq", "", "Specify a jq filter for modifying the output.")
+	rootCmd.Flags().BoolP("exit-status", "e", false, "Set the exit status to 1 if the last result of the jq filter is false or null, or 4 if there is no result.")
+	rootCmd.Flags().BoolP("join-output", "j", false, "Like --raw-output, but do not write a newline between the results of the jq filter.")
+	rootCmd.Flags().String("jq", "", "Specify a jq filter for modifying the output. Besides the jq builtins, fromkv, tokv, fromcsv, tocsv, fromyaml, toyaml, flatten_paths, unflatten_paths, parse_duration, parse_bytes, parse_semver and @table are available.")
+	rootCmd.Flags().Bool("jsonargs", false, "Pass the remaining arguments to the jq filter as positional JSON arguments ($ARGS.positional) instead of reading them as file.")
+	rootCmd.Flags().String("jq-results", "collect", `How to write multiple results of the jq filter: "collect" (as one list), "each" (one after another), or "single" (fail).`)
+	rootCmd.Flags().StringSlice("column-width", nil, "Limit the width of table columns, given as [NAME=]WIDTH[:truncate|wrap]. Without NAME, the limit applies to all other columns.")
+	rootCmd.Flags().StringArray("column-format", nil, "Render the values of table columns, given as [NAME=]SPEC[,SPEC...]. Without NAME, the format applies to all other columns. "+
+		"SPEC is one of left, right, center, numbers (right-align numeric columns), thousands, precision:N, bytes, duration (nanoseconds), "+
+		"time (Unix timestamps and times), relative, layout:LAYOUT (e.g., datetime or a Go time layout), tz:ZONE (e.g., UTC or Local).")
+	rootCmd.Flags().String("delimiter", "", "Use the given character as field delimiter for CSV input.")
+	rootCmd.Flags().String("fields", "", "Select, order and rename the fields of the output records, e.g., 'name,email:Mail,age'.")
+	rootCmd.Flags().Bool("flatten", false, "Expand nested values into columns named by their path, e.g., 'parent.child' (csv, table, text, tsv).")
+	rootCmd.Flags().Int("flatten-depth", 0, "Limit the number of nested levels expanded by --flatten (0 means unlimited).")
+	rootCmd.Flags().String("flatten-index", "dot", `Name array elements expanded by --flatten like "items.0" ("dot") or "items[0]" ("bracket").`)
+	rootCmd.Flags().String("flatten-separator", ".", "Join the names of nested values expanded by --flatten with the given separator.")
+	rootCmd.Flags().StringArray("highlight", nil, "Color table cells matching a rule of the form FIELD OP VALUE:COLOR[:row], e.g., 'status=FAILED:red' or 'age>30:yellow:row'. "+
+		"Operators: =, !=, <, <=, >, >=, ~ (regular expression), !~. The suffix ':row' colors the whole record.")
+	rootCmd.Flags().Bool("infer-types", false, "Convert CSV and TSV fields that look like booleans or numbers to the respective type.")
+	rootCmd.Flags().StringP("input", "i", input.Auto, "The format of the input ("+strings.Join(append([]string{input.Auto}, input.Names()...), ", ")+").")
+	rootCmd.Flags().StringArrayP("library-path", "L", nil, "Search the directory for jq modules, before ~/.jq, .jq and $ORIGIN/../lib/jq. "+
+		"If ~/.jq or .jq is a file, its functions are available to the jq filter.")
+	rootCmd.Flags().Int("limit", 0, "Write at most the given number of elements (0 means unlimited), after sorting and skipping.")
 	rootCmd.Flags().Bool("list-themes", false, "Display a list of supported themes for syntax highlighting.")
-	rootCmd.Flags().StringP("output", "o", "", "The formatting style for command output (csv, json, table, text, tsv, yaml).")
+	rootCmd.Flags().Int("offset", 0, "Skip the given number of elements, after sorting.")
+	rootCmd.Flags().BoolP("null-input", "n", false, "Apply the jq filter once to null. The documents of the input are available via input and inputs.")
+	rootCmd.Flags().StringP("output", "o", "", "The formatting style for command output ("+strings.Join(gfmt.DefaultRegistry.Names(), ", ")+").")
+	rootCmd.Flags().String("missing", "", "The placeholder for table cells, whose record does not have the column (csv, table, text, tsv).")
+	rootCmd.Flags().Bool("no-pager", false, "Do not pipe the output through $GUTENFMT_PAGER, $PAGER or '"+gfmt.DefaultPager+"', if it is taller than the terminal.")
+	rootCmd.Flags().Bool("no-header", false, "Treat the first row of CSV and TSV input as data and name the columns column1, column2, etc.")
+	rootCmd.Flags().Bool("per-document", false, "Apply the JMESPath query to each document of a multi-document input separately. The jq filter is always applied to each document, unless --slurp is set.")
+	rootCmd.Flags().StringArray("rawfile", nil, "Set a jq variable to the content of a file as string, given as NAME=FILE.")
 	rootCmd.Flags().String("pretty", "auto", `Pretty-print the output (JSON or YAML). Possible values are "true"/"always", "false"/"never", "auto".`)
 	rootCmd.Flags().StringP("query", "q", "", "Specify a JMESPath query to use in filtering the output")
 	rootCmd.Flags().BoolP("raw-output", "r", false, "If the filter's result is a string, then it will be written directly to standard output rather than being formatted as a JSON string with quotes.")
-	rootCmd.Flags().String("theme", theme, "Set the theme for syntax highlighting. Use '--list-themes' to see all available themes.")
+	rootCmd.Flags().Bool("seq", false, "Write an ASCII record separator before and a line feed after each result of the jq filter (RFC 7464).")
+	rootCmd.Flags().BoolP("slurp", "s", false, "Apply the jq filter once to an array of all documents of the input.")
+	rootCmd.Flags().StringArray("slurpfile", nil, "Set a jq variable to an array of the JSON values in a file, given as NAME=FILE.")
+	rootCmd.Flags().String("sort", "", "Sort elements by fields, e.g., '-age:numeric,name'. Prefix a field with '-' for descending order. "+
+		"Comparisons: auto, lexical, numeric, natural, time.")
+	rootCmd.Flags().BoolP("sort-keys", "S", false, "Write the keys of objects returned by the jq filter in sorted order instead of the order of the input and of the object constructions.")
+	rootCmd.Flags().String("table-style", gfmt.StylePlain.Name, "The borders of the table output ("+strings.Join(gfmt.TableStyleNames(), ", ")+").")
+	rootCmd.Flags().String("theme", theme, "Set the theme for syntax highlighting and colored tables and text. Use '--list-themes' to see all available themes.")
+	rootCmd.Flags().StringP("vertical", "x", "off", `Print each record of a table as block of name and value lines. Possible values are "on", "off", "auto" (if wider than the terminal).`)
+	rootCmd.Flags().Lookup("vertical").NoOptDefVal = "on"
 
 	rootCmd.MarkFlagsMutuallyExclusive("jq", "query")
+	rootCmd.MarkFlagsMutuallyExclusive("args", "jsonargs")
 	rootCmd.Flags().VisitAll(func(f *pflag.Flag) {
 		if f.Name != "list-themes" {
 			rootCmd.MarkFlagsMutuallyExclusive("list-themes", f.Name)
 		}
 	})
 
+	rootCmd.Long = longHelp()
+	rootCmd.AddCommand(formatsCmd)
+
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Println(err)
 		os.Exit(1)
 	}
 	fmt.Println()
+	os.Exit(exitStatus)
+}
+
+// longHelp returns the description of the root command including all registered formats.
+func longHelp() string {
+	b := &strings.Builder{}
+	b.WriteString("The gutenfmt utility formats its input to various output formats.\n\n")
+	b.WriteString("Supported input formats (detected automatically unless --input is given):\n")
+	for _, f := range input.Default.Formats() {
+		_, _ = fmt.Fprintf(b, "- %s: %s.\n", f.Name, f.Description)
+	}
+
+	b.WriteString("\nThe following output formats are supported:\n")
+	for _, f := range gfmt.DefaultRegistry.Formats() {
+		_, _ = fmt.Fprintf(b, "- %s: %s", f.Name, f.Description)
+		if f.Name == "json" {
+			b.WriteString(" This setting is the default.")
+		}
+		if f.Pretty {
+			b.WriteString(" Optionally, use --pretty.")
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
 }
 
-// parse attempts to detect the input format e.g., JSON and returns the value,
-// which could be a key-value pairs (map) or a slice thereof.
-func parse(name string) any {
-	var err error
+// jqOnlyFlags holds the names of the flags, which require --jq.
+var jqOnlyFlags = []string{"arg", "argjson", "args", "exit-status", "join-output", "jsonargs",
+	"library-path", "null-input", "rawfile", "seq", "slurp", "slurpfile", "sort-keys"}
+
+// jqOnly reports whether any flag is set, which requires --jq.
+func jqOnly(fs *pflag.FlagSet) bool {
+	for _, n := range jqOnlyFlags {
+		if fs.Changed(n) {
+			return true
+		}
+	}
+	return false
+}
+
+// positional reports whether the arguments are passed to the jq filter (--args or --jsonargs).
+func positional(fs *pflag.FlagSet) bool {
+	args, _ := fs.GetBool("args")
+	jsonArgs, _ := fs.GetBool("jsonargs")
+	return args || jsonArgs
+}
+
+// jqOptions returns the options of the jq filter, which are set by flags.
+// The positional arguments are passed as strings or JSON values, depending on --args and --jsonargs.
+func jqOptions(fs *pflag.FlagSet, out io.Writer, pos []string) ([]gfmt.Opt[gfmt.JQ], error) {
+	opts := []gfmt.Opt[gfmt.JQ]{gfmt.WithBuiltins()}
+	if raw, _ := fs.GetBool("raw-output"); raw {
+		opts = append(opts, gfmt.WithRaw())
+	}
+	rs, _ := fs.GetString("jq-results")
+	res, err := gfmt.ParseResults(rs)
+	if err != nil {
+		return nil, err
+	} else if res == gfmt.ResultsEach {
+		opts = append(opts, gfmt.WithSeparator(out, "\n"))
+	} else {
+		opts = append(opts, gfmt.WithResults(res))
+	}
+
+	if seq, _ := fs.GetBool("seq"); seq {
+		opts = append(opts, gfmt.WithSeq(out))
+	}
+	if join, _ := fs.GetBool("join-output"); join {
+		opts = append(opts, gfmt.WithRaw(), gfmt.WithSeparator(out, ""))
+	}
+	if slurp, _ := fs.GetBool("slurp"); slurp {
+		opts = append(opts, gfmt.WithSlurp())
+	}
+	if nullIn, _ := fs.GetBool("null-input"); nullIn {
+		opts = append(opts, gfmt.WithNullInput())
+	}
+	if sortKeys, _ := fs.GetBool("sort-keys"); sortKeys {
+		opts = append(opts, gfmt.WithSortKeys[gfmt.JQ]())
+	}
+
+	// Directories given by --library-path are searched before the default paths.
+	lib, _ := fs.GetStringArray("library-path")
+	opts = append(opts, gfmt.WithModulePaths(append(lib, gfmt.DefaultModulePaths...)...))
+
+	for _, n := range []string{"slurpfile", "rawfile"} {
+		fls, _ := fs.GetStringArray(n)
+		for _, f := range fls {
+			k, v, ok := strings.Cut(f, "=")
+			if !ok || k == "" {
+				return nil, fmt.Errorf("invalid --%s: %q (expected NAME=FILE)", n, f)
+			}
+			opts = append(opts, gfmt.WithVarFile(gfmt.VarFile{Name: k, Path: v, Raw: n == "rawfile"}))
+		}
+	}
+
+	str, _ := fs.GetBool("args")
+	for _, p := range pos {
+		opts = append(opts, gfmt.WithPositional(gfmt.Arg{Val: p, String: str}))
+	}
+	return append(opts, gfmt.WithInputs()), nil
+}
+
+// parse reads the named file or standard input, if name is "-", and decodes
+// it using the given input format.
+func parse(name, format string) (any, error) {
 	r := os.Stdin
 	if name != "-" {
-		r, err = os.Open(name)
+		f, err := os.Open(name)
 		if err != nil {
-			log.Fatalln(err)
+			return nil, err
 		}
-		defer func() { _ = r.Close() }()
+		defer func() { _ = f.Close() }()
+		r = f
 	}
+	return input.Decode(r, format)
+}
 
-	bs, err := io.ReadAll(r)
-	if err != nil {
-		log.Fatalln(err) //nolint:gocritic
+// configureCSV replaces the CSV and TSV decoders, if any of the CSV-related flags is set.
+// The delimiter applies to CSV only.
+func configureCSV(fs *pflag.FlagSet) error {
+	if !fs.Changed("no-header") && !fs.Changed("infer-types") && !fs.Changed("delimiter") {
+		return nil
+	}
+	noHeader, _ := fs.GetBool("no-header")
+	infer, _ := fs.GetBool("infer-types")
+	delim, _ := fs.GetString("delimiter")
+	if delim != "" && utf8.RuneCountInString(delim) != 1 {
+		return fmt.Errorf("invalid delimiter: %q", delim)
 	}
 
-	var m any
-	kv := map[string]any{}
-	d := json.NewDecoder(bytes.NewReader(bs))
-	if err = d.Decode(&m); err != nil {
-		s := bufio.NewScanner(bytes.NewReader(bs))
-		for s.Scan() {
-			if idx := bytes.IndexAny(s.Bytes(), "=:\t"); idx > 0 {
-				kv[string(s.Bytes()[:idx])] = string(s.Bytes()[idx+1:])
-			}
+	for n, minFields := range map[string]int{"csv": input.CSVSniffFields, "tsv": input.TSVSniffFields} {
+		f, ok := input.Lookup(n)
+		d, isCSV := f.Decoder.(input.CSV)
+		if !ok || !isCSV {
+			// replaced by a custom decoder, which does not know about the flags
+			continue
 		}
+		d.NoHeader, d.InferTypes = noHeader, infer
+		if n == "csv" && delim != "" {
+			d.Comma, _ = utf8.DecodeRuneInString(delim)
+		}
+		f.Decoder, f.Sniff = d, d.Sniff(minFields)
+		input.Register(f)
 	}
-	if len(kv) > 0 {
-		return kv
+	return nil
+}
+
+// parseColumns parses column width limits of the form [NAME=]WIDTH[:truncate|wrap].
+func parseColumns(specs []string) (map[string]gfmt.Column, error) {
+	if len(specs) == 0 {
+		return nil, nil
+	}
+	cols := make(map[string]gfmt.Column, len(specs))
+	for _, s := range specs {
+		name, spec, ok := strings.Cut(s, "=")
+		if !ok {
+			name, spec = "", s
+		}
+		spec, mode, _ := strings.Cut(spec, ":")
+
+		n, err := strconv.Atoi(spec)
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("invalid column width: %q", s)
+		}
+		c := gfmt.Column{MaxWidth: n}
+		switch strings.ToLower(mode) {
+		case "", "truncate":
+			c.Overflow = gfmt.OverflowTruncate
+		case "wrap":
+			c.Overflow = gfmt.OverflowWrap
+		default:
+			return nil, fmt.Errorf("invalid column overflow: %q", s)
+		}
+		cols[name] = c
+	}
+	return cols, nil
+}
+
+// timeLayouts maps names to Go time layouts.
+var timeLayouts = map[string]string{
+	"dateonly": time.DateOnly,
+	"datetime": time.DateTime,
+	"kitchen":  time.Kitchen,
+	"rfc1123":  time.RFC1123,
+	"rfc1123z": time.RFC1123Z,
+	"rfc3339":  time.RFC3339,
+	"timeonly": time.TimeOnly,
+}
+
+// parseColumnFormats parses the presentation of column values of the form [NAME=]SPEC[,SPEC...]
+// and adds it to cols.
+// Named columns inherit all settings, which they do not define, from the column without name.
+func parseColumnFormats(specs []string, cols map[string]gfmt.Column) (map[string]gfmt.Column, error) {
+	if len(specs) == 0 {
+		return cols, nil
+	} else if cols == nil {
+		cols = make(map[string]gfmt.Column, len(specs))
+	}
+
+	for _, s := range specs {
+		name, spec, ok := strings.Cut(s, "=")
+		if !ok {
+			name, spec = "", s
+		}
+		c := cols[name]
+		for _, f := range strings.Split(spec, ",") {
+			k, v, _ := strings.Cut(strings.TrimSpace(f), ":")
+			switch strings.ToLower(k) {
+			case "left":
+				c.Align = gfmt.AlignLeft
+			case "right":
+				c.Align = gfmt.AlignRight
+			case "center":
+				c.Align = gfmt.AlignCenter
+			case "numbers":
+				c.Align = gfmt.AlignNumbers
+			case "thousands":
+				c.Thousands = true
+			case "precision":
+				p, err := strconv.Atoi(v)
+				if err != nil || p < 0 {
+					return nil, fmt.Errorf("invalid precision in column format: %q", s)
+				}
+				c.Precision = &p
+			case "bytes":
+				c.Unit = gfmt.UnitBytes
+			case "duration":
+				c.Unit = gfmt.UnitDuration
+			case "time":
+				c.Unit = gfmt.UnitTime
+			case "relative":
+				c.Relative = true
+			case "layout":
+				if l, ok := timeLayouts[strings.ToLower(v)]; ok {
+					v = l
+				}
+				c.TimeLayout = v
+			case "tz":
+				loc, err := time.LoadLocation(v)
+				if err != nil {
+					return nil, fmt.Errorf("invalid time zone in column format: %q: %w", s, err)
+				}
+				c.Location = loc
+			default:
+				return nil, fmt.Errorf("invalid column format: %q", s)
+			}
+		}
+		cols[name] = c
 	}
-	return m
+	return cols, nil
 }
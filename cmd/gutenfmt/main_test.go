@@ -0,0 +1,100 @@
+// Copyright 2026 The gutenfmt authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestMain runs the command instead of the tests, if the test binary is
+// executed by run.
+func TestMain(m *testing.M) {
+	if os.Getenv("GUTENFMT_TEST_MAIN") == "1" {
+		os.Args = append([]string{"gutenfmt"}, os.Args[1:]...)
+		main()
+	}
+	os.Exit(m.Run())
+}
+
+// run executes the command with the given arguments and returns its standard output.
+func run(t *testing.T, args ...string) string {
+	t.Helper()
+	cmd := exec.Command(os.Args[0], append([]string{"--no-pager", "--theme", ""}, args...)...)
+	cmd.Env = append(os.Environ(), "GUTENFMT_TEST_MAIN=1")
+	out, err := cmd.Output()
+	require.NoError(t, err, string(out))
+	return string(out)
+}
+
+func TestJQ_Stream(t *testing.T) {
+	f := filepath.Join(t.TempDir(), "in.ndjson")
+	require.NoError(t, os.WriteFile(f, []byte("{\"a\":1}\n{\"a\":2}\n{\"a\":3}\n{\"a\":4}\n"), 0o600))
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"collect", []string{"--jq", ".a"}, "[1,2,3,4]\n"},
+		{"each", []string{"--jq", ".a", "--jq-results", "each"}, "1\n2\n3\n4\n"},
+		{"slurp", []string{"--jq", "map(.a) | add", "-s"}, "10\n"},
+		{"slurp_each", []string{"--jq", "length", "-s", "--jq-results", "each"}, "4\n"},
+		{"input", []string{"--jq", "[.a, input.a]"}, "[[1,2],[3,4]]\n"},
+		{"null_input", []string{"--jq", "[inputs.a]", "-n"}, "[1,2,3,4]\n"},
+		{"null_input_input", []string{"--jq", "input.a", "-n"}, "1\n"},
+		{"raw", []string{"--jq", `"x\(.a)"`, "-r"}, "x1\nx2\nx3\nx4\n"},
+		{"table", []string{"--jq", "{a, b: (.a * 2)}", "-o", "table"}, "a   b   \n1   2   \n2   4   \n3   6   \n4   8\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, run(t, append(tt.args, f)...))
+		})
+	}
+}
+
+func TestJQ_Single(t *testing.T) {
+	f := filepath.Join(t.TempDir(), "in.json")
+	require.NoError(t, os.WriteFile(f, []byte(`{"a":1,"b":{"c":2}}`), 0o600))
+
+	require.Equal(t, "1\n", run(t, "--jq", ".a", f))
+	require.Equal(t, "c: 2\n", run(t, "--jq", ".b", "-o", "yaml", f))
+}
+
+func TestJQ_Error(t *testing.T) {
+	f := filepath.Join(t.TempDir(), "in.ndjson")
+	require.NoError(t, os.WriteFile(f, []byte("{\"a\":1}\n{\"a\":\"x\"}\n"), 0o600))
+
+	cmd := exec.Command(os.Args[0], "--jq", ".a + 1", f)
+	cmd.Env = append(os.Environ(), "GUTENFMT_TEST_MAIN=1")
+	out, err := cmd.Output()
+	require.Error(t, err)
+	require.Equal(t, "2", string(out))
+}
+
+func TestCSV_Delimiter(t *testing.T) {
+	dir := t.TempDir()
+	csv, tsv := filepath.Join(dir, "in.csv"), filepath.Join(dir, "in.tsv")
+	require.NoError(t, os.WriteFile(csv, []byte("a;b\n1;2\n"), 0o600))
+	require.NoError(t, os.WriteFile(tsv, []byte("a\tb\tc\n1\t2\t3\n"), 0o600))
+
+	require.Equal(t, `[{"a":"1","b":"2"}]`+"\n", run(t, "--delimiter", ";", "-i", "auto", "-o", "json", csv))
+	require.Equal(t, `[{"a":"1","b":"2","c":"3"}]`+"\n", run(t, "--delimiter", ";", "-i", "tsv", "-o", "json", tsv))
+}
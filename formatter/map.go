@@ -17,15 +17,26 @@ package formatter
 import (
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
 
 	"github.com/abc-inc/gutenfmt/internal/render"
+	"github.com/abc-inc/gutenfmt/ordered"
 )
 
-// FromMap creates a Formatter that outputs all map entries in unspecified order.
+// FromMap creates a Formatter that outputs all map entries.
+// Entries of an ordered.Map are formatted in order, others in unspecified order.
 func FromMap(sep, delim string) Formatter {
 	return Func(func(i any) (string, error) {
-		return FromMapKeys(sep, delim, reflect.ValueOf(i).MapKeys()...).Format(i)
+		return FromMapKeys(sep, delim, MapKeys(i, false)...).Format(i)
+	})
+}
+
+// FromMapSorted creates a Formatter that outputs all map entries sorted by key.
+// Entries of an ordered.Map keep their order.
+func FromMapSorted(sep, delim string) Formatter {
+	return Func(func(i any) (string, error) {
+		return FromMapKeys(sep, delim, MapKeys(i, true)...).Format(i)
 	})
 }
 
@@ -34,10 +45,9 @@ func FromMap(sep, delim string) Formatter {
 // If a key is given multiple times, it will be rendered multiple times.
 func FromMapKeys(sep, delim string, ks ...reflect.Value) Formatter {
 	return Func(func(i any) (string, error) {
-		m := reflect.ValueOf(i)
 		b := &strings.Builder{}
 		for _, mk := range ks {
-			mv := m.MapIndex(mk)
+			mv := MapIndex(i, mk)
 			s := ""
 			if mv.IsValid() {
 				s = render.ToString(mv.Interface())
@@ -52,82 +62,151 @@ func FromMapKeys(sep, delim string, ks ...reflect.Value) Formatter {
 }
 
 // FromMapSlice creates a Formatter that formats a slice of maps.
+// The columns are determined by the keys of all elements, in order of their
+// first occurrence (see MapSliceKeys).
+// Keys of an ordered.Map are formatted in order, others in unspecified order.
 func FromMapSlice(sep, delim string) Formatter {
-	contains := func(es []string, s string) bool {
-		for _, e := range es {
-			if e == s {
-				return true
-			}
-		}
-		return false
-	}
-
-	return Func(func(mapSlice any) (string, error) {
-		v := reflect.ValueOf(mapSlice)
-		if v.Len() == 0 {
-			return "", nil
-		}
-
-		b := &strings.Builder{}
-
-		var ks []string
-		e := reflect.ValueOf(v.Index(0).Interface())
-		for i, k := range e.MapKeys() {
-			if i > 0 {
-				b.WriteString(sep)
-			}
-			n := render.ToString(k.Interface())
-			if !contains(ks, n) {
-				ks = append(ks, n)
-				b.WriteString(n)
-			}
-		}
+	return fromMapSlice(sep, delim, false)
+}
 
-		for i := 0; i < v.Len(); i++ {
-			b.WriteString(delim)
-			m := reflect.ValueOf(v.Index(i).Interface())
-			for idx, k := range ks {
-				if idx > 0 {
-					b.WriteString(sep)
-				}
-				if val := m.MapIndex(reflect.ValueOf(k)); val.IsValid() {
-					b.WriteString(render.ToString(val))
-				}
-			}
-		}
+// FromMapSliceSorted creates a Formatter that formats a slice of maps.
+// Unlike FromMapSlice, keys of regular maps are sorted.
+func FromMapSliceSorted(sep, delim string) Formatter {
+	return fromMapSlice(sep, delim, true)
+}
 
-		return b.String(), nil
+func fromMapSlice(sep, delim string, sorted bool) Formatter {
+	return Func(func(mapSlice any) (string, error) {
+		return FromMapSliceKeys(sep, delim, MapSliceKeys(mapSlice, sorted)...).Format(mapSlice)
 	})
 }
 
 // FromMapSliceKeys creates a Formatter that outputs a slice of maps.
 func FromMapSliceKeys(sep, delim string, ks ...reflect.Value) Formatter {
+	return FromMapSliceKeysMissing(sep, delim, "", ks...)
+}
+
+// FromMapSliceKeysMissing creates a Formatter that outputs a slice of maps.
+// Unlike FromMapSliceKeys, missing entries are formatted as the given placeholder.
+func FromMapSliceKeysMissing(sep, delim, missing string, ks ...reflect.Value) Formatter {
 	if len(ks) == 0 {
 		return NoopFormatter()
 	}
-
 	return Func(func(mapSlice any) (string, error) {
 		v := reflect.ValueOf(mapSlice)
 		b := &strings.Builder{}
-
 		b.WriteString(render.ToString(ks[0].Interface()))
 		for idx := 1; idx < len(ks); idx++ {
 			b.WriteString(sep)
 			b.WriteString(render.ToString(ks[idx].Interface()))
 		}
-
 		for i := 0; i < v.Len(); i++ {
 			b.WriteString(delim)
 			for idx, k := range ks {
 				if idx > 0 {
 					b.WriteString(sep)
 				}
-				if val := v.Index(i).MapIndex(k); val.IsValid() {
-					b.WriteString(render.ToString(val))
+				if val := MapIndex(v.Index(i).Interface(), k); val.IsValid() {
+					b.WriteString(render.ToString(val.Interface()))
+				} else {
+					b.WriteString(missing)
 				}
 			}
 		}
-
 		return b.String(), nil
 	})
 }
+
+// MapSliceKeys infers the columns of a slice of maps.
+// It returns the union of the keys of all elements in order of their first
+// occurrence. Keys are considered equal, if their string representations are.
+//
+// If sorted is true and none of the elements is an ordered.Map, the keys are
+// sorted by their string representation.
+func MapSliceKeys(mapSlice any, sorted bool) []reflect.Value {
+	v := reflect.ValueOf(mapSlice)
+	var ks []reflect.Value
+	seen := map[string]bool{}
+	anyOrdered := false
+	for idx := 0; idx < v.Len(); idx++ {
+		e := v.Index(idx).Interface()
+		if rv := reflect.ValueOf(e); !IsMap(e) || (rv.Kind() == reflect.Ptr && rv.IsNil()) {
+			continue
+		}
+		_, ok := asOrdered(e)
+		anyOrdered = anyOrdered || ok
+		for _, k := range MapKeys(e, sorted) {
+			if n := render.ToString(k.Interface()); !seen[n] {
+				seen[n] = true
+				ks = append(ks, k)
+			}
+		}
+	}
+	if sorted && !anyOrdered {
+		sort.SliceStable(ks, func(a, b int) bool {
+			return render.ToString(ks[a].Interface()) < render.ToString(ks[b].Interface())
+		})
+	}
+	return ks
+}
+
+// IsMap returns true if i is a map, an ordered.Map or a pointer to either.
+func IsMap(i any) bool {
+	switch i.(type) {
+	case ordered.Map, *ordered.Map:
+		return true
+	}
+	v := reflect.Indirect(reflect.ValueOf(i))
+	return v.Kind() == reflect.Map
+}
+
+// MapKeys returns the keys of a map, an ordered.Map or a pointer to either.
+// Keys of an ordered.Map are returned in order, keys of regular maps are sorted
+// by their string representation, if requested.
+// Otherwise, their order is unspecified.
+func MapKeys(i any, sorted bool) []reflect.Value {
+	if om, ok := asOrdered(i); ok {
+		ks := make([]reflect.Value, om.Len())
+		for idx, k := range om.Keys() {
+			ks[idx] = reflect.ValueOf(k)
+		}
+		return ks
+	}
+
+	ks := reflect.Indirect(reflect.ValueOf(i)).MapKeys()
+	if sorted {
+		sort.SliceStable(ks, func(a, b int) bool {
+			return render.ToString(ks[a].Interface()) < render.ToString(ks[b].Interface())
+		})
+	}
+	return ks
+}
+
+// MapIndex returns the value for a key in a map, an ordered.Map or a pointer to either.
+// It returns the zero Value, if the key is not present or i is not a map.
+func MapIndex(i any, k reflect.Value) reflect.Value {
+	if om, ok := asOrdered(i); ok {
+		if v, ok := om.Get(render.ToString(k.Interface())); ok {
+			return reflect.ValueOf(v)
+		}
+		return reflect.Value{}
+	}
+	v := reflect.Indirect(reflect.ValueOf(i))
+	if v.Kind() != reflect.Map || !k.Type().AssignableTo(v.Type().Key()) {
+		return reflect.Value{}
+	}
+	return v.MapIndex(k)
+}
+
+// asOrdered returns the ordered.Map, if i is one or a pointer to one.
+func asOrdered(i any) (ordered.Map, bool) {
+	switch t := i.(type) {
+	case ordered.Map:
+		return t, true
+	case *ordered.Map:
+		if t != nil {
+			return *t, true
+		}
+	}
+	return ordered.Map{}, false
+}
@@ -19,6 +19,7 @@ import (
 	"testing"
 
 	"github.com/abc-inc/gutenfmt/formatter"
+	"github.com/abc-inc/gutenfmt/ordered"
 	"github.com/stretchr/testify/require"
 )
 
@@ -55,3 +56,56 @@ func TestFromMapSlice(t *testing.T) {
 	s, _ := f.Format([]map[string]bool{truth})
 	require.Regexp(t, "(y\tn\t\ntrue\tfalse)|(n\ty\t\nfalse\ttrue)", s)
 }
+
+func TestFromMap_ordered(t *testing.T) {
+	m := ordered.NewMap(2)
+	m.Set("z", 1)
+	m.Set("a", "x")
+	f := formatter.FromMap("=", "\n")
+	s, _ := f.Format(m)
+	require.Equal(t, "z=1\na=x\n", s)
+
+	s, _ = formatter.FromMapSorted("=", "\n").Format(*m)
+	require.Equal(t, "z=1\na=x\n", s)
+}
+
+func TestFromMapSorted(t *testing.T) {
+	f := formatter.FromMapSorted("\t", "\t\n")
+	s, _ := f.Format(map[int]string{10: "c", 2: "b", 1: "a"})
+	require.Equal(t, "1\ta\t\n10\tc\t\n2\tb\t\n", s)
+}
+
+func TestFromMapSlice_ordered(t *testing.T) {
+	m1, m2 := ordered.NewMap(2), ordered.NewMap(2)
+	m1.Set("y", 1)
+	m1.Set("n", 2)
+	m2.Set("n", 3)
+	m2.Set("y", 4)
+	s, _ := formatter.FromMapSlice("\t", "\n").Format([]any{m1, m2})
+	require.Equal(t, "y\tn\n1\t2\n4\t3", s)
+}
+
+func TestFromMapSliceSorted(t *testing.T) {
+	f := formatter.FromMapSliceSorted("\t", "\t\n")
+	s, _ := f.Format([]map[string]bool{truth, truth})
+	require.Equal(t, "n\ty\t\nfalse\ttrue\t\nfalse\ttrue", s)
+}
+
+func TestFromMapSlice_union(t *testing.T) {
+	m1, m2 := ordered.NewMap(1), ordered.NewMap(2)
+	m1.Set("y", 1)
+	m2.Set("x", 2)
+	m2.Set("y", 3)
+	s, _ := formatter.FromMapSlice(",", "\n").Format([]any{m1, nil, m2})
+	require.Equal(t, "y,x\n1,\n,\n3,2", s)
+
+	s, _ = formatter.FromMapSliceSorted(",", "\n").Format([]map[string]int{{"c": 1}, {"b": 2, "a": 3}})
+	require.Equal(t, "a,b,c\n,,1\n3,2,", s)
+}
+
+func TestFromMapSliceKeysMissing(t *testing.T) {
+	ms := []map[string]int{{"a": 1}, {"b": 2}}
+	f := formatter.FromMapSliceKeysMissing(",", "\n", "-", formatter.MapSliceKeys(ms, true)...)
+	s, _ := f.Format(ms)
+	require.Equal(t, "a,b\n1,-\n-,2", s)
+}
@@ -0,0 +1,180 @@
+// Copyright 2026 The gutenfmt authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gfmt
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/abc-inc/gutenfmt/formatter"
+	"github.com/abc-inc/gutenfmt/internal/render"
+	"github.com/abc-inc/gutenfmt/internal/table"
+	"github.com/abc-inc/gutenfmt/ordered"
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/formatters"
+)
+
+// painter colors text with the colors of a chroma Style.
+//
+// Since the widths of cells are measured without escape sequences, painted
+// text can be aligned like plain text.
+type painter struct {
+	style    *chroma.Style
+	sortKeys bool
+	// stringers treats values implementing fmt.Stringer as scalar values.
+	stringers bool
+	// render renders nested values of maps and slices without colors, render.ToString if nil.
+	render func(i any) string
+}
+
+// newPainter returns a painter for the Style or nil, if no colors should be used.
+func newPainter(s *chroma.Style, sortKeys bool) *painter {
+	if s == nil || s.Name == "noop" {
+		return nil
+	}
+	return &painter{style: s, sortKeys: sortKeys}
+}
+
+// paint colors text like a token of the given type.
+func (p painter) paint(t chroma.TokenType, text string) string {
+	if text == "" {
+		return text
+	}
+	b := &strings.Builder{}
+	if err := formatters.TTY8.Format(b, p.style, chroma.Literator(chroma.Token{Type: t, Value: text})); err != nil {
+		return text
+	}
+	return b.String()
+}
+
+// punctuation colors separators and borders.
+func (p painter) punctuation(text string) string {
+	return p.paint(chroma.Punctuation, text)
+}
+
+// value colors a scalar value according to its type.
+func (p painter) value(i any) string {
+	s := render.ToString(i)
+	switch i.(type) {
+	case nil:
+		return s
+	case bool:
+		return p.paint(chroma.KeywordConstant, s)
+	case string:
+		if strings.Contains(s, "\x1b[") {
+			// already colored e.g., by a Highlight
+			return s
+		}
+		return p.paint(chroma.LiteralString, s)
+	case json.Number:
+		return p.paint(chroma.LiteralNumber, s)
+	case time.Time, *time.Time:
+		return p.paint(chroma.LiteralDate, s)
+	}
+	switch reflect.ValueOf(i).Kind() { //nolint:exhaustive
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return p.paint(chroma.LiteralNumber, s)
+	}
+	return p.paint(chroma.Text, s)
+}
+
+// colorize converts records and slices to ordered.Maps and slices holding
+// colored keys and values.
+// Column names of record slices are colored as headings, other keys as names.
+// Nested values are rendered like uncolored ones, before they are colored.
+func (p painter) colorize(i any) any {
+	if _, ok := i.(fmt.Stringer); ok && p.stringers && !formatter.IsMap(i) {
+		return p.value(i)
+	}
+
+	o := table.Options{SortKeys: p.sortKeys}
+	if r, ok := table.Record(i, o); ok {
+		// Structs are formatted like record slices, whereas map values are written one by one.
+		nested := render.ToString
+		if formatter.IsMap(i) {
+			nested = p.nestedRender()
+		}
+		c := ordered.NewMap(r.Len())
+		for _, k := range r.Keys() {
+			e, _ := r.Get(k)
+			c.Set(p.paint(chroma.NameTag, k), p.colorizeNested(e, nested))
+		}
+		return c
+	}
+
+	v := reflect.ValueOf(i)
+	switch v.Kind() { //nolint:exhaustive
+	case reflect.Ptr:
+		if !v.IsNil() {
+			return p.colorize(v.Elem().Interface())
+		}
+		return nil
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return p.value(i)
+		}
+	default:
+		return p.value(i)
+	}
+
+	if v.Len() == 0 || !table.IsRecord(v.Index(0).Interface()) {
+		cs := make([]any, v.Len())
+		for idx := range cs {
+			cs[idx] = p.colorizeNested(v.Index(idx).Interface(), p.nestedRender())
+		}
+		return cs
+	}
+
+	_, rs, _ := table.Records(i, o)
+	cs := make([]any, len(rs))
+	for idx, r := range rs {
+		c := ordered.NewMap(r.Len())
+		for _, k := range r.Keys() {
+			e, _ := r.Get(k)
+			c.Set(p.paint(chroma.GenericHeading, k), p.colorizeNested(e, render.ToString))
+		}
+		cs[idx] = c
+	}
+	return cs
+}
+
+// nestedRender returns the function, which renders nested values of maps and slices.
+func (p painter) nestedRender() func(i any) string {
+	if p.render != nil {
+		return p.render
+	}
+	return render.ToString
+}
+
+// colorizeNested colors a value within a record or slice.
+// Records and slices are rendered as a whole, so that colors do not change the content.
+// Nil values are kept, so that they are still rendered as empty cells.
+func (p painter) colorizeNested(i any, nested func(i any) string) any {
+	if i == nil {
+		return nil
+	}
+	v := reflect.Indirect(reflect.ValueOf(i))
+	if !v.IsValid() {
+		return nil
+	} else if k := v.Kind(); table.IsRecord(i) || ((k == reflect.Slice || k == reflect.Array) && v.Type().Elem().Kind() != reflect.Uint8) {
+		return p.paint(chroma.Text, nested(i))
+	}
+	return p.value(i)
+}
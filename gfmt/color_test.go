@@ -0,0 +1,122 @@
+// Copyright 2026 The gutenfmt authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gfmt_test
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/abc-inc/gutenfmt/gfmt"
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/styles"
+	"github.com/stretchr/testify/require"
+)
+
+var ansi = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+func TestColor_Tab(t *testing.T) {
+	ms := []any{
+		newOrderedMap("name", "日本", "n", 1, "ok", true, "x", nil),
+		newOrderedMap("name", "bob", "n", 22.5, "ok", false, "x", "s"),
+	}
+
+	for _, ts := range []gfmt.TableStyle{gfmt.StylePlain, gfmt.StyleRounded, gfmt.StyleCompact} {
+		t.Run(ts.Name, func(t *testing.T) {
+			plain, colored := &strings.Builder{}, &strings.Builder{}
+			_, err := gfmt.NewTab(plain, gfmt.WithTableStyle(ts)).Write(ms)
+			require.NoError(t, err)
+			_, err = gfmt.NewTab(colored, gfmt.WithTableStyle(ts), gfmt.WithStyle[gfmt.Tab](styles.Get("native"))).Write(ms)
+			require.NoError(t, err)
+
+			require.NotEqual(t, plain.String(), colored.String())
+			require.Equal(t, plain.String(), ansi.ReplaceAllString(colored.String(), ""))
+		})
+	}
+}
+
+func TestColor_TabValues(t *testing.T) {
+	b := &strings.Builder{}
+	_, err := gfmt.NewTab(b, gfmt.WithStyle[gfmt.Tab](styles.Get("native"))).Write(newOrderedMap("n", 1, "s", "x", "b", true))
+	require.NoError(t, err)
+	require.Equal(t, "\x1b[1m\x1b[32mn\x1b[0m   \x1b[36m1\x1b[0m    \n"+
+		"\x1b[1m\x1b[32ms\x1b[0m   \x1b[1m\x1b[33mx\x1b[0m    \n"+
+		"\x1b[1m\x1b[32mb\x1b[0m   \x1b[1m\x1b[32mtrue\x1b[0m \n", b.String())
+}
+
+func TestColor_Text(t *testing.T) {
+	u := NewUser("John", "Doe")
+	for _, v := range []any{u, []*User{u}, newOrderedMap("a", 1, "b", newOrderedMap("c", "d"))} {
+		plain, colored := &strings.Builder{}, &strings.Builder{}
+		_, err := gfmt.NewText(plain).Write(v)
+		require.NoError(t, err)
+		_, err = gfmt.NewText(colored, gfmt.WithStyle[gfmt.Text](styles.Get("native"))).Write(v)
+		require.NoError(t, err)
+
+		require.NotEqual(t, plain.String(), colored.String())
+		require.Equal(t, plain.String(), ansi.ReplaceAllString(colored.String(), ""))
+	}
+}
+
+func TestColor_Disabled(t *testing.T) {
+	b := &strings.Builder{}
+	_, err := gfmt.NewTab(b, gfmt.WithStyle[gfmt.Tab](styles.Fallback)).Write(newOrderedMap("n", 1))
+	require.NoError(t, err)
+	require.Equal(t, "n   1   \n", b.String())
+}
+
+func TestColor_Nested(t *testing.T) {
+	type amount struct{ C int }
+	type item struct {
+		Amt amount
+		L   []int
+	}
+	tab := func(b *strings.Builder, s *chroma.Style) gfmt.Writer {
+		return gfmt.NewTab(b, gfmt.WithStyle[gfmt.Tab](s))
+	}
+	text := func(b *strings.Builder, s *chroma.Style) gfmt.Writer {
+		return gfmt.NewText(b, gfmt.WithStyle[gfmt.Text](s))
+	}
+	it := item{amount{150}, []int{1, 2}}
+
+	tests := []struct {
+		name  string
+		w     func(b *strings.Builder, s *chroma.Style) gfmt.Writer
+		v     any
+		plain string
+		want  string
+	}{
+		// Records are colored as ordered.Maps, which are terminated by a newline in contrast to structs.
+		{"tab", tab, it, "Amt {150} \nL   1 2", "Amt {150} \nL   1 2   \n"},
+		{"tab_slice", tab, []item{it}, "Amt   L   \n{150} 1 2", "Amt   L   \n{150} 1 2"},
+		{"text", text, it, "Amt:{150}\nL:1 2", "Amt:{150}\nL:1 2"},
+		{"text_slice", text, []item{it}, "Amt:L\n{150}:1 2", "Amt:L\n{150}:1 2"},
+		{"text_map", text, newOrderedMap("Amt", it.Amt, "L", it.L), "Amt:C:150\nL:1\n2", "Amt:C:150\nL:1\n2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plain, colored := &strings.Builder{}, &strings.Builder{}
+			_, err := tt.w(plain, nil).Write(tt.v)
+			require.NoError(t, err)
+			_, err = tt.w(colored, styles.Get("native")).Write(tt.v)
+			require.NoError(t, err)
+
+			require.Equal(t, tt.plain, plain.String())
+			require.NotEqual(t, tt.want, colored.String())
+			require.Equal(t, tt.want, ansi.ReplaceAllString(colored.String(), ""))
+		})
+	}
+}
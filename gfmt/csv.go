@@ -0,0 +1,171 @@
+// Copyright 2026 The gutenfmt authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gfmt
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"io"
+	"reflect"
+
+	"github.com/abc-inc/gutenfmt/formatter"
+	"github.com/abc-inc/gutenfmt/internal/table"
+)
+
+// Nested determines how nested values like maps, structs and slices are encoded.
+type Nested int
+
+const (
+	// NestedJSON encodes nested values as JSON.
+	NestedJSON Nested = iota
+	// NestedFlatten expands nested values into columns named by their path e.g., "parent.child".
+	NestedFlatten
+)
+
+// CSV is a generic Writer that formats arbitrary values as comma-separated values according to RFC 4180.
+//
+// Slices of maps and structs are written as one record per element, preceded by a header.
+// A single map or struct is written as one record per entry, each consisting of name and value.
+type CSV struct {
+	writer    io.Writer
+	Formatter *formatter.CompFormatter
+	// Comma is the field delimiter.
+	Comma rune
+	// Header enables the header record.
+	Header bool
+	// CRLF uses \r\n as line terminator instead of \n.
+	CRLF bool
+	// BOM writes the UTF-8 byte order mark first, which is required by some spreadsheet applications.
+	BOM bool
+	// Nested determines how nested values are encoded.
+	Nested Nested
+	// Flatten configures NestedFlatten. If not nil, Nested is ignored and nested values are flattened.
+	Flatten *Flatten
+	// SortKeys sorts the keys of maps, which do not define an order.
+	SortKeys bool
+	// Missing is the placeholder for cells, whose record does not have the column's key.
+	Missing string
+}
+
+// NewCSV creates a new CSV Writer.
+func NewCSV(w io.Writer, opts ...Opt[CSV]) *CSV {
+	gw := &CSV{writer: w, Formatter: formatter.NewComp(), Comma: ',', Header: true}
+	for _, opt := range opts {
+		opt(gw)
+	}
+	return gw
+}
+
+// Write writes the CSV representation of the given value to the underlying Writer.
+// The line terminator of the last record is omitted.
+func (w CSV) Write(i any) (int, error) {
+	if i == nil {
+		return 0, nil
+	}
+
+	if s, err := w.Formatter.Format(i); err == nil {
+		return io.WriteString(w.writer, s)
+	} else if !errors.Is(err, formatter.ErrUnsupported) {
+		return 0, err
+	}
+
+	f := w.Flatten
+	if f == nil && w.Nested == NestedFlatten {
+		f = &Flatten{}
+	}
+	o := f.options(w.SortKeys)
+	o.Missing = w.Missing
+	t, ok := table.From(i, o)
+	if !ok {
+		if typ := reflect.TypeOf(i); typ.Kind() == reflect.Ptr {
+			return w.Write(reflect.Indirect(reflect.ValueOf(i)).Interface())
+		}
+		t = &table.Table{Rows: [][]any{{i}}}
+	}
+
+	b := &bytes.Buffer{}
+	if w.BOM {
+		b.WriteString("\ufeff")
+	}
+	cw := csv.NewWriter(b)
+	cw.Comma = w.Comma
+	cw.UseCRLF = w.CRLF
+
+	if w.Header && t.Header != nil {
+		if err := cw.Write(t.Header); err != nil {
+			return 0, err
+		}
+	}
+	rec := make([]string, 0, len(t.Header))
+	for _, row := range t.Rows {
+		rec = rec[:0]
+		for _, c := range row {
+			s, err := cellString(c)
+			if err != nil {
+				return 0, err
+			}
+			rec = append(rec, s)
+		}
+		if err := cw.Write(rec); err != nil {
+			return 0, err
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return 0, err
+	}
+
+	s := bytes.TrimSuffix(b.Bytes(), []byte("\n"))
+	if w.CRLF {
+		s = bytes.TrimSuffix(s, []byte("\r"))
+	}
+	return w.writer.Write(s)
+}
+
+// WithDelimiter sets the field delimiter of a CSV Writer.
+func WithDelimiter(r rune) Opt[CSV] {
+	return func(w *CSV) {
+		w.Comma = r
+	}
+}
+
+// WithoutHeader omits the header record of a CSV Writer.
+func WithoutHeader() Opt[CSV] {
+	return func(w *CSV) {
+		w.Header = false
+	}
+}
+
+// WithCRLF uses \r\n as line terminator of a CSV Writer.
+func WithCRLF() Opt[CSV] {
+	return func(w *CSV) {
+		w.CRLF = true
+	}
+}
+
+// WithBOM writes the UTF-8 byte order mark before the records of a CSV Writer.
+func WithBOM() Opt[CSV] {
+	return func(w *CSV) {
+		w.BOM = true
+	}
+}
+
+// WithNested sets the encoding of nested values of a CSV Writer.
+func WithNested(n Nested) Opt[CSV] {
+	return func(w *CSV) {
+		w.Nested = n
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright 2026 The gutenfmt authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gfmt_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/abc-inc/gutenfmt/gfmt"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCSV_Write(t *testing.T) {
+	tests := []struct {
+		name string
+		arg  any
+		want string
+	}{
+		{"nil", nil, ""},
+		{"bool", false, "false"},
+		{"string", "a,b", `"a,b"`},
+		{"int_slice", []int{1, 2, 3}, "1\n2\n3"},
+		{"struct", NewUser("John", "Doe"), "username,John Doe\nemail,john.doe@local"},
+		{"struct_slice", []User{*NewUser("John", "Doe")}, "username,email\nJohn Doe,john.doe@local"},
+		{"empty_struct_slice", []User{}, "username,email"},
+		{"map_slice", []any{newOrderedMap("a", "x,y", "b", "say \"hi\""), newOrderedMap("a", "1\n2")},
+			"a,b\n\"x,y\",\"say \"\"hi\"\"\"\n\"1\n2\","},
+		{"nested", []any{newOrderedMap("a", []any{1, "x"}, "b", newOrderedMap("c", true))}, "a,b\n\"[1,\"\"x\"\"]\",\"{\"\"c\"\":true}\""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &strings.Builder{}
+			_, err := gfmt.NewCSV(b).Write(tt.arg)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, b.String())
+		})
+	}
+}
+
+func TestCSV_WriteOptions(t *testing.T) {
+	in := []any{newOrderedMap("a", 1, "b", newOrderedMap("c", []any{2, 3}))}
+
+	b := &strings.Builder{}
+	n, err := gfmt.NewCSV(b, gfmt.WithDelimiter(';'), gfmt.WithCRLF(), gfmt.WithBOM(),
+		gfmt.WithNested(gfmt.NestedFlatten)).Write(in)
+	require.NoError(t, err)
+	require.Equal(t, "\ufeffa;b.c.0;b.c.1\r\n1;2;3", b.String())
+	require.Equal(t, b.Len(), n)
+
+	b.Reset()
+	_, err = gfmt.NewCSV(b, gfmt.WithoutHeader(), gfmt.WithSortKeys[gfmt.CSV]()).Write([]map[string]int{{"b": 1, "a": 2}})
+	require.NoError(t, err)
+	require.Equal(t, "2,1", b.String())
+}
+
+func TestCSV_WriteSchema(t *testing.T) {
+	b := &strings.Builder{}
+	_, err := gfmt.NewCSV(b, gfmt.WithMissing[gfmt.CSV]("n/a"), gfmt.WithSortKeys[gfmt.CSV]()).
+		Write([]map[string]any{{"c": 1}, {"b": 2, "a": nil}})
+	require.NoError(t, err)
+	require.Equal(t, "a,b,c\nn/a,n/a,1\n,2,n/a", b.String())
+}
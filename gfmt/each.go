@@ -0,0 +1,60 @@
+// Copyright 2026 The gutenfmt authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gfmt
+
+import (
+	"io"
+	"reflect"
+)
+
+// Each is a Writer that passes every element of a slice or array separately
+// to the delegate Writer, e.g., to apply a jq filter to each document of a stream.
+// Other values are passed as is.
+type Each struct {
+	writer Writer
+	out    io.Writer
+	Delim  string
+}
+
+// NewEach creates a new Writer that writes the elements one by one.
+// Delim is written to out between two consecutive elements.
+func NewEach(delegate Writer, out io.Writer, delim string) *Each {
+	return &Each{delegate, out, delim}
+}
+
+// Write writes each element of i to the delegate Writer.
+func (w Each) Write(i any) (int, error) {
+	v := reflect.ValueOf(i)
+	if k := v.Kind(); k != reflect.Slice && k != reflect.Array {
+		return w.writer.Write(i)
+	}
+
+	cnt := 0
+	for idx := 0; idx < v.Len(); idx++ {
+		if idx > 0 {
+			n, err := io.WriteString(w.out, w.Delim)
+			cnt += n
+			if err != nil {
+				return cnt, err
+			}
+		}
+		n, err := w.writer.Write(v.Index(idx).Interface())
+		cnt += n
+		if err != nil {
+			return cnt, err
+		}
+	}
+	return cnt, nil
+}
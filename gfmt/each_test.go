@@ -0,0 +1,39 @@
+// Copyright 2026 The gutenfmt authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gfmt_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/abc-inc/gutenfmt/gfmt"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEach_Write(t *testing.T) {
+	b := &strings.Builder{}
+	jq, err := gfmt.NewJQ(gfmt.NewJSON(b), ".a")
+	require.NoError(t, err)
+	w := gfmt.NewEach(jq, b, "\n")
+	n, err := w.Write([]any{map[string]any{"a": 1}, map[string]any{"a": []int{2}}})
+	require.NoError(t, err)
+	require.Equal(t, "1\n[2]", b.String())
+	require.Equal(t, 5, n)
+
+	b.Reset()
+	_, err = w.Write(map[string]any{"a": "x"})
+	require.NoError(t, err)
+	require.Equal(t, `"x"`, b.String())
+}
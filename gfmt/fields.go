@@ -0,0 +1,116 @@
+// Copyright 2026 The gutenfmt authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gfmt
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/abc-inc/gutenfmt/internal/table"
+	"github.com/abc-inc/gutenfmt/ordered"
+)
+
+// Field selects a field of a record and optionally renames it.
+type Field struct {
+	// Name is the map key or the name of the struct field, as determined by meta.Resolve.
+	Name string
+	// Label is the name in the output. If empty, Name is used.
+	Label string
+}
+
+// ParseFields parses a comma-separated list of fields, each of which may be
+// followed by a colon and a label e.g., "name,email:Mail,age".
+func ParseFields(s string) ([]Field, error) {
+	var fs []Field
+	for _, f := range strings.Split(s, ",") {
+		n, l, _ := strings.Cut(strings.TrimSpace(f), ":")
+		if n = strings.TrimSpace(n); n == "" {
+			return nil, fmt.Errorf("invalid field list: %q", s)
+		}
+		fs = append(fs, Field{n, strings.TrimSpace(l)})
+	}
+	return fs, nil
+}
+
+// Fields is a Writer that selects, orders and renames the fields of records,
+// i.e., maps and structs, before passing them to the delegate Writer.
+//
+// Slices of records are projected element by element.
+// Missing fields result in nil values, other values are passed as is.
+type Fields struct {
+	writer Writer
+	Fields []Field
+}
+
+// NewFields creates a new Writer that projects records to the given fields.
+func NewFields(delegate Writer, fs ...Field) *Fields {
+	return &Fields{delegate, fs}
+}
+
+// Write projects i and writes the result to the delegate Writer.
+func (w Fields) Write(i any) (int, error) {
+	return w.writer.Write(w.project(i))
+}
+
+// project converts records to ordered.Maps holding the selected fields only.
+func (w Fields) project(i any) any {
+	if r, ok := table.Record(i, table.Options{}); ok {
+		p := ordered.NewMap(len(w.Fields))
+		for _, f := range w.Fields {
+			l := f.Label
+			if l == "" {
+				l = f.Name
+			}
+			p.Set(l, lookupField(r, f.Name))
+		}
+		return p
+	}
+
+	v := reflect.ValueOf(i)
+	switch v.Kind() { //nolint:exhaustive
+	case reflect.Ptr:
+		if !v.IsNil() {
+			return w.project(v.Elem().Interface())
+		}
+	case reflect.Slice, reflect.Array:
+		ps := make([]any, v.Len())
+		recs := false
+		for idx := range ps {
+			e := v.Index(idx).Interface()
+			recs = recs || table.IsRecord(e)
+			ps[idx] = w.project(e)
+		}
+		if recs {
+			return ps
+		}
+	}
+	return i
+}
+
+// lookupField returns the value of the named field.
+// If there is no exact match, the name is matched case-insensitively.
+func lookupField(r *ordered.Map, name string) any {
+	if e, ok := r.Get(name); ok {
+		return e
+	}
+	for _, k := range r.Keys() {
+		if strings.EqualFold(k, name) {
+			e, _ := r.Get(k)
+			return e
+		}
+	}
+	return nil
+}
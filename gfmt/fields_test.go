@@ -0,0 +1,69 @@
+// Copyright 2026 The gutenfmt authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gfmt_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/abc-inc/gutenfmt/gfmt"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFields(t *testing.T) {
+	fs, err := gfmt.ParseFields("name, email:Mail ,age")
+	require.NoError(t, err)
+	require.Equal(t, []gfmt.Field{{"name", ""}, {"email", "Mail"}, {"age", ""}}, fs)
+
+	_, err = gfmt.ParseFields("name,,age")
+	require.Error(t, err)
+	_, err = gfmt.ParseFields(":Mail")
+	require.Error(t, err)
+}
+
+func TestFields_Write(t *testing.T) {
+	fs, _ := gfmt.ParseFields("email:Mail,username,missing")
+	users := []*User{NewUser("John", "Doe"), NewUser("Jane", "Roe")}
+
+	b := &strings.Builder{}
+	_, err := gfmt.NewFields(gfmt.NewTab(b), fs...).Write(users)
+	require.NoError(t, err)
+	require.Equal(t, "Mail           username missing \njohn.doe@local John Doe         \njane.roe@local Jane Roe ", b.String())
+
+	b.Reset()
+	_, err = gfmt.NewFields(gfmt.NewCSV(b), fs...).Write(users[0])
+	require.NoError(t, err)
+	require.Equal(t, "Mail,john.doe@local\nusername,John Doe\nmissing,", b.String())
+
+	b.Reset()
+	ms := []map[string]any{{"username": "x", "email": "x@local", "age": 1}}
+	_, err = gfmt.NewFields(gfmt.NewYAML(b), fs[:2]...).Write(ms)
+	require.NoError(t, err)
+	require.Equal(t, "- Mail: x@local\n  username: x", b.String())
+}
+
+func TestFields_WriteCaseInsensitive(t *testing.T) {
+	b := &strings.Builder{}
+	_, err := gfmt.NewFields(gfmt.NewJSON(b), gfmt.Field{Name: "USERNAME"}).Write(NewUser("John", "Doe"))
+	require.NoError(t, err)
+	require.Equal(t, `{"USERNAME":"John Doe"}`, b.String())
+}
+
+func TestFields_WriteScalars(t *testing.T) {
+	b := &strings.Builder{}
+	_, err := gfmt.NewFields(gfmt.NewJSON(b), gfmt.Field{Name: "a"}).Write([]int{1, 2})
+	require.NoError(t, err)
+	require.Equal(t, "[1,2]", b.String())
+}
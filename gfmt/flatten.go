@@ -0,0 +1,64 @@
+// Copyright 2026 The gutenfmt authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gfmt
+
+import (
+	"reflect"
+
+	"github.com/abc-inc/gutenfmt/internal/table"
+)
+
+// Flatten determines how nested maps, structs, slices and arrays are expanded
+// into columns named by their path e.g., "parent.child" or "items[0]".
+type Flatten struct {
+	// MaxDepth limits the number of nested levels, which are expanded, 0 means unlimited.
+	MaxDepth int
+	// Separator joins the names of nested values, "." if empty.
+	Separator string
+	// Brackets names array elements like "items[0]" instead of "items.0".
+	Brackets bool
+}
+
+// options returns the table.Options for the Flatten settings.
+// A nil Flatten disables flattening.
+func (f *Flatten) options(sortKeys bool) table.Options {
+	o := table.Options{SortKeys: sortKeys}
+	if f != nil {
+		o.Flatten, o.MaxDepth, o.Separator, o.Brackets = true, f.MaxDepth, f.Separator, f.Brackets
+	}
+	return o
+}
+
+// flatten converts a record or a slice of records to ordered.Maps with flattened entries.
+// Other values are returned as is.
+func (f *Flatten) flatten(i any, sortKeys bool) any {
+	o := f.options(sortKeys)
+	if r, ok := table.Record(i, o); ok {
+		return r
+	}
+
+	v := reflect.ValueOf(i)
+	if v.Kind() == reflect.Ptr && !v.IsNil() {
+		return f.flatten(v.Elem().Interface(), sortKeys)
+	} else if k := v.Kind(); (k != reflect.Slice && k != reflect.Array) || v.Len() == 0 || !table.IsRecord(v.Index(0).Interface()) {
+		return i
+	}
+	_, rs, _ := table.Records(i, o)
+	flat := make([]any, len(rs))
+	for idx, r := range rs {
+		flat[idx] = r
+	}
+	return flat
+}
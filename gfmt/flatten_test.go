@@ -0,0 +1,45 @@
+// Copyright 2026 The gutenfmt authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gfmt_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/abc-inc/gutenfmt/gfmt"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlatten(t *testing.T) {
+	nested := []any{newOrderedMap("id", 1, "user", newOrderedMap("name", "a", "tags", []any{"x", "y"})),
+		newOrderedMap("id", 2, "user", nil)}
+
+	b := &strings.Builder{}
+	_, err := gfmt.NewTab(b, gfmt.WithFlatten[gfmt.Tab](gfmt.Flatten{})).Write(nested)
+	require.NoError(t, err)
+	require.Equal(t, "id  user.name user.tags.0 user.tags.1 user \n1   a         x           y                \n2"+strings.Repeat(" ", 37), b.String())
+
+	b.Reset()
+	_, err = gfmt.NewCSV(b, gfmt.WithFlatten[gfmt.CSV](gfmt.Flatten{Brackets: true, Separator: "/"})).Write(nested)
+	require.NoError(t, err)
+	require.Equal(t, "id,user/name,user/tags[0],user/tags[1],user\n1,a,x,y,\n2,,,,", b.String())
+
+	b.Reset()
+	tw := gfmt.NewText(b, gfmt.WithFlatten[gfmt.Text](gfmt.Flatten{MaxDepth: 1}))
+	tw.Sep = "="
+	_, err = tw.Write(nested[0])
+	require.NoError(t, err)
+	require.Equal(t, "id=1\nuser.name=a\nuser.tags=x\ny", b.String())
+}
@@ -0,0 +1,211 @@
+// Copyright 2026 The gutenfmt authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gfmt
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/alecthomas/chroma/v2"
+)
+
+// ErrUnknownFormat is the error resulting if no output format is registered for a name.
+var ErrUnknownFormat = errors.New("unknown output format")
+
+// Config holds the settings, which are passed to a Factory.
+// A Factory ignores the settings, which are not supported by the format.
+type Config struct {
+	// Pretty enables pretty-printing.
+	Pretty bool
+	// Style sets the syntax highlighting style, nil disables highlighting.
+	Style *chroma.Style
+	// Table sets the borders and separators of tables, the zero value keeps the default.
+	Table TableStyle
+	// Columns limits the width of table columns by name (see Tab.Columns).
+	Columns map[string]Column
+	// Vertical prints the records of tables as blocks of name and value lines.
+	Vertical Vertical
+	// Width is the available width e.g., of the terminal, 0 means unknown.
+	Width int
+	// Flatten expands nested values of records into separate columns, if not nil.
+	Flatten *Flatten
+	// Missing is the placeholder for cells, whose record does not have the column's key.
+	Missing string
+	// Highlights colors the table cells or rows of records, which match a condition.
+	Highlights []Highlight
+}
+
+// Factory creates a new Writer for the output format.
+type Factory func(w io.Writer, c Config) Writer
+
+// Format describes a named output format.
+type Format struct {
+	// Name is the unique name of the format e.g., "json".
+	Name string
+	// Description is a short, human-readable description of the format.
+	Description string
+	// Pretty reports whether the format supports pretty-printing.
+	Pretty bool
+	// Style reports whether the format supports syntax highlighting.
+	Style bool
+	// Extensions holds the file extensions including the leading dot e.g., ".json".
+	Extensions []string
+	// New creates a new Writer.
+	New Factory
+}
+
+// Registry holds named output formats.
+type Registry struct {
+	formats []Format
+}
+
+// NewRegistry creates a new Registry without any formats.
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Register adds the Format to the Registry.
+// If a Format with the same name already exists, it is replaced.
+func (r *Registry) Register(f Format) {
+	for idx := range r.formats {
+		if r.formats[idx].Name == f.Name {
+			r.formats[idx] = f
+			return
+		}
+	}
+	r.formats = append(r.formats, f)
+	sort.Slice(r.formats, func(i, j int) bool { return r.formats[i].Name < r.formats[j].Name })
+}
+
+// Lookup returns the Format registered under the given name (case-insensitive).
+func (r *Registry) Lookup(name string) (Format, bool) {
+	for _, f := range r.formats {
+		if strings.EqualFold(f.Name, name) {
+			return f, true
+		}
+	}
+	return Format{}, false
+}
+
+// LookupExt returns the first Format, which handles the given file extension (case-insensitive).
+func (r *Registry) LookupExt(ext string) (Format, bool) {
+	for _, f := range r.formats {
+		for _, e := range f.Extensions {
+			if strings.EqualFold(e, ext) {
+				return f, true
+			}
+		}
+	}
+	return Format{}, false
+}
+
+// Formats returns all registered formats sorted by name.
+func (r *Registry) Formats() []Format {
+	return append([]Format(nil), r.formats...)
+}
+
+// Names returns the names of all registered formats in sorted order.
+func (r *Registry) Names() []string {
+	ns := make([]string, len(r.formats))
+	for idx, f := range r.formats {
+		ns[idx] = f.Name
+	}
+	return ns
+}
+
+// New creates a new Writer for the named format.
+func (r *Registry) New(name string, w io.Writer, c Config) (Writer, error) {
+	f, ok := r.Lookup(name)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownFormat, name)
+	}
+	return f.New(w, c), nil
+}
+
+// DefaultRegistry is the Registry, which holds the built-in formats and is used by the package-level functions.
+var DefaultRegistry = NewRegistry()
+
+func init() {
+	DefaultRegistry.Register(Format{"csv", "Comma-separated values.", false, false, []string{".csv"},
+		func(w io.Writer, c Config) Writer {
+			cw := NewCSV(w)
+			cw.Flatten, cw.Missing = c.Flatten, c.Missing
+			return cw
+		}})
+	DefaultRegistry.Register(Format{"html", "HTML table.", false, false, []string{".html", ".htm"},
+		func(w io.Writer, _ Config) Writer {
+			return NewHTML(w)
+		}})
+	DefaultRegistry.Register(Format{"json", "JSON string.", true, true, []string{".json"},
+		func(w io.Writer, c Config) Writer {
+			opts := []Opt[JSON]{WithStyle[JSON](c.Style)}
+			if c.Pretty {
+				opts = append(opts, WithPretty[JSON]())
+			}
+			return NewJSON(w, opts...)
+		}})
+	DefaultRegistry.Register(Format{"markdown", "GitHub Flavored Markdown table or list.", false, false, []string{".md", ".markdown"},
+		func(w io.Writer, _ Config) Writer {
+			return NewMarkdown(w)
+		}})
+	DefaultRegistry.Register(Format{"table", "ASCII table, optionally with borders (see --table-style).", false, true, nil,
+		func(w io.Writer, c Config) Writer {
+			tw := NewTab(w, WithStyle[Tab](c.Style))
+			if c.Table.Name != "" {
+				tw.TableStyle = c.Table
+			}
+			tw.Columns, tw.Vertical, tw.Width = c.Columns, c.Vertical, c.Width
+			tw.Flatten, tw.Missing, tw.Highlights = c.Flatten, c.Missing, c.Highlights
+			return tw
+		}})
+	DefaultRegistry.Register(Format{"text", "Name and value pairs, separated by equal sign.", false, true, []string{".txt"},
+		func(w io.Writer, c Config) Writer {
+			tw := NewText(w, WithStyle[Text](c.Style))
+			tw.Sep, tw.Flatten, tw.Missing = "=", c.Flatten, c.Missing
+			return tw
+		}})
+	DefaultRegistry.Register(Format{"tsv", "Tab-separated name and value pairs (useful for grep, sed, or awk).", false, false, []string{".tsv"},
+		func(w io.Writer, c Config) Writer {
+			tw := NewText(w)
+			tw.Sep, tw.Flatten, tw.Missing = "\t", c.Flatten, c.Missing
+			return tw
+		}})
+	DefaultRegistry.Register(Format{"yaml", "YAML, a machine-readable alternative to JSON.", true, true, []string{".yaml", ".yml"},
+		func(w io.Writer, c Config) Writer {
+			opts := []Opt[YAML]{WithStyle[YAML](c.Style)}
+			if c.Pretty {
+				opts = append(opts, WithPretty[YAML]())
+			}
+			return NewYAML(w, opts...)
+		}})
+}
+
+// RegisterFormat adds the Format to the default Registry, so that applications can provide their own Writers.
+func RegisterFormat(f Format) {
+	DefaultRegistry.Register(f)
+}
+
+// LookupFormat returns the Format registered under the given name in the default Registry.
+func LookupFormat(name string) (Format, bool) {
+	return DefaultRegistry.Lookup(name)
+}
+
+// NewWriter creates a new Writer for the named format in the default Registry.
+func NewWriter(name string, w io.Writer, c Config) (Writer, error) {
+	return DefaultRegistry.New(name, w, c)
+}
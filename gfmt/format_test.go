@@ -0,0 +1,90 @@
+// Copyright 2026 The gutenfmt authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gfmt_test
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/abc-inc/gutenfmt/gfmt"
+	"github.com/alecthomas/chroma/v2/styles"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewWriter(t *testing.T) {
+	tests := []struct {
+		name   string
+		pretty bool
+		want   string
+	}{
+		{"csv", false, "a,b\n1,2"},
+		{"JSON", false, `[{"a":1,"b":2}]`},
+		{"json", true, "[\n  {\n    \"a\": 1,\n    \"b\": 2\n  }\n]"},
+		{"table", false, "a   b   \n1   2"},
+		{"text", false, "a=b\n1=2"},
+		{"tsv", false, "a\tb\n1\t2"},
+		{"yaml", false, "- a: 1\n  b: 2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &strings.Builder{}
+			w, err := gfmt.NewWriter(tt.name, b, gfmt.Config{Pretty: tt.pretty, Style: styles.Fallback})
+			require.NoError(t, err)
+			_, err = w.Write([]any{newOrderedMap("a", 1, "b", 2)})
+			require.NoError(t, err)
+			require.Equal(t, tt.want, b.String())
+		})
+	}
+
+	_, err := gfmt.NewWriter("unknown", io.Discard, gfmt.Config{})
+	require.ErrorIs(t, err, gfmt.ErrUnknownFormat)
+}
+
+func TestRegistry(t *testing.T) {
+	r := gfmt.NewRegistry()
+	r.Register(gfmt.Format{Name: "upper", Extensions: []string{".up"}, New: func(w io.Writer, _ gfmt.Config) gfmt.Writer {
+		return gfmt.WrapIOWriter(w)
+	}})
+	r.Register(gfmt.Format{Name: "lower", New: func(w io.Writer, _ gfmt.Config) gfmt.Writer {
+		return gfmt.WrapIOWriter(w)
+	}})
+	require.Equal(t, []string{"lower", "upper"}, r.Names())
+
+	f, ok := r.LookupExt(".UP")
+	require.True(t, ok)
+	require.Equal(t, "upper", f.Name)
+	_, ok = r.LookupExt(".json")
+	require.False(t, ok)
+
+	f, ok = gfmt.DefaultRegistry.LookupExt(".yml")
+	require.True(t, ok)
+	require.Equal(t, "yaml", f.Name)
+}
+
+func TestRegisterFormat(t *testing.T) {
+	gfmt.RegisterFormat(gfmt.Format{Name: "test-upper", New: func(w io.Writer, _ gfmt.Config) gfmt.Writer {
+		jq, _ := gfmt.NewJQ(gfmt.WrapIOWriter(w), "ascii_upcase", gfmt.WithRaw())
+		return jq
+	}})
+
+	f, ok := gfmt.LookupFormat("test-upper")
+	require.True(t, ok)
+	b := &strings.Builder{}
+	_, err := f.New(b, gfmt.Config{}).Write("abc")
+	require.NoError(t, err)
+	require.Equal(t, "ABC", b.String())
+}
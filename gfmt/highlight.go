@@ -0,0 +1,146 @@
+// Copyright 2026 The gutenfmt authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gfmt
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/abc-inc/gutenfmt/internal/render"
+	"github.com/abc-inc/gutenfmt/ordered"
+)
+
+// sgrCodes maps color and attribute names to ANSI Select Graphic Rendition parameters.
+var sgrCodes = map[string]string{
+	"bold": "1", "faint": "2", "italic": "3", "underline": "4", "reverse": "7",
+	"black": "30", "red": "31", "green": "32", "yellow": "33",
+	"blue": "34", "magenta": "35", "cyan": "36", "white": "37", "gray": "90",
+	"bright-red": "91", "bright-green": "92", "bright-yellow": "93",
+	"bright-blue": "94", "bright-magenta": "95", "bright-cyan": "96", "bright-white": "97",
+}
+
+// highlightCond splits a condition like "age>=30" into field, operator and value.
+var highlightCond = regexp.MustCompile(`^([^=!<>~]+)(!=|>=|<=|!~|=|>|<|~)(.*)$`)
+
+// Highlight colors the cells of records, whose field matches a condition.
+type Highlight struct {
+	// Field is the map key or the name of the struct field, as determined by meta.Resolve.
+	Field string
+	// Match reports whether the value of the field matches the condition.
+	Match func(v any) bool
+	// Color is a color name like "red" or "bright-yellow", or an attribute like "bold".
+	// Multiple names can be combined with "+" e.g., "bold+red".
+	Color string
+	// Row colors all cells of the record instead of the field only.
+	Row bool
+}
+
+// ParseHighlight parses a rule of the form FIELD OP VALUE:COLOR[:row].
+//
+// OP is one of "=", "!=", "<", "<=", ">", ">=", which compare numerically, if
+// both sides are numbers, or lexically otherwise, and "~" or "!~", which
+// match a regular expression. The suffix ":row" colors the whole record.
+//
+// Examples: "status=FAILED:red", "age>30:yellow", "name~^j:bold+cyan:row".
+func ParseHighlight(s string) (Highlight, error) {
+	h := Highlight{}
+	parts := strings.Split(s, ":")
+	if len(parts) > 2 && parts[len(parts)-1] == "row" {
+		h.Row, parts = true, parts[:len(parts)-1]
+	}
+	if len(parts) < 2 {
+		return h, fmt.Errorf("invalid highlight rule, color missing: %q", s)
+	}
+	h.Color = strings.ToLower(parts[len(parts)-1])
+	if _, ok := sgr(h.Color); !ok {
+		return h, fmt.Errorf("invalid color in highlight rule: %q", s)
+	}
+
+	m := highlightCond.FindStringSubmatch(strings.Join(parts[:len(parts)-1], ":"))
+	if m == nil {
+		return h, fmt.Errorf("invalid condition in highlight rule: %q", s)
+	}
+	h.Field = strings.TrimSpace(m[1])
+	op, val := m[2], strings.TrimSpace(m[3])
+
+	switch op {
+	case "~", "!~":
+		re, err := regexp.Compile(val)
+		if err != nil {
+			return h, fmt.Errorf("invalid regular expression in highlight rule: %q: %w", s, err)
+		}
+		h.Match = func(v any) bool {
+			return re.MatchString(render.ToString(v)) == (op == "~")
+		}
+	default:
+		h.Match = func(v any) bool {
+			c := compare(CompareAuto, v, val)
+			switch op {
+			case "=":
+				return c == 0
+			case "!=":
+				return c != 0
+			case "<":
+				return c < 0
+			case "<=":
+				return c <= 0
+			case ">":
+				return c > 0
+			default:
+				return c >= 0
+			}
+		}
+	}
+	return h, nil
+}
+
+// sgr returns the escape sequence for a color like "bold+red".
+func sgr(color string) (string, bool) {
+	var ps []string
+	for _, n := range strings.Split(color, "+") {
+		p, ok := sgrCodes[strings.TrimSpace(n)]
+		if !ok {
+			return "", false
+		}
+		ps = append(ps, p)
+	}
+	return "\x1b[" + strings.Join(ps, ";") + "m", true
+}
+
+// colors returns the escape sequences of the values of a record, which match any Highlight.
+func (w Tab) colors(r *ordered.Map) map[string]string {
+	colors := map[string]string{}
+	for _, h := range w.Highlights {
+		seq, ok := sgr(h.Color)
+		if !ok || h.Match == nil || !h.Match(lookupField(r, h.Field)) {
+			continue
+		}
+		for _, k := range r.Keys() {
+			if _, done := colors[k]; !done && (h.Row || strings.EqualFold(k, h.Field)) {
+				colors[k] = seq
+			}
+		}
+	}
+	return colors
+}
+
+// WithHighlight adds rules for coloring cells of a Tab Writer.
+// If multiple rules match a cell, the first one wins.
+func WithHighlight(hs ...Highlight) Opt[Tab] {
+	return func(w *Tab) {
+		w.Highlights = append(w.Highlights, hs...)
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright 2026 The gutenfmt authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gfmt_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/abc-inc/gutenfmt/gfmt"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseHighlight(t *testing.T) {
+	tests := []struct {
+		rule  string
+		field string
+		color string
+		row   bool
+		match []any
+		skip  []any
+	}{
+		{"status=FAILED:red", "status", "red", false, []any{"FAILED"}, []any{"OK", nil}},
+		{"age>30:yellow", "age", "yellow", false, []any{31, "40", 30.5}, []any{30, 7, nil}},
+		{"age<=30:Bold+Green:row", "age", "bold+green", true, []any{30, 7}, []any{31}},
+		{"name ~ ^j.*:cyan", "name", "cyan", false, []any{"jo"}, []any{"Jo"}},
+		{"name!~^J:cyan", "name", "cyan", false, []any{"jo"}, []any{"Jo"}},
+		{"start>=2026-01-01T00:00:00Z:blue", "start", "blue", false, []any{"2026-03-01T10:00:00Z"}, []any{"2025-12-31T23:59:59Z"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			h, err := gfmt.ParseHighlight(tt.rule)
+			require.NoError(t, err)
+			require.Equal(t, tt.field, h.Field)
+			require.Equal(t, tt.color, h.Color)
+			require.Equal(t, tt.row, h.Row)
+			for _, v := range tt.match {
+				require.True(t, h.Match(v), "%v", v)
+			}
+			for _, v := range tt.skip {
+				require.False(t, h.Match(v), "%v", v)
+			}
+		})
+	}
+}
+
+func TestParseHighlightInvalid(t *testing.T) {
+	for _, rule := range []string{"status=FAILED", "status=FAILED:purple", "FAILED:red", "name~(:red"} {
+		_, err := gfmt.ParseHighlight(rule)
+		require.Error(t, err, rule)
+	}
+}
+
+func TestTab_WriteHighlight(t *testing.T) {
+	jobs := []any{
+		newOrderedMap("name", "build", "status", "OK", "age", 12),
+		newOrderedMap("name", "test", "status", "FAILED", "age", 34),
+	}
+	failed, _ := gfmt.ParseHighlight("status=FAILED:red")
+	old, _ := gfmt.ParseHighlight("age>30:yellow:row")
+
+	b := &strings.Builder{}
+	_, err := gfmt.NewTab(b, gfmt.WithHighlight(failed)).Write(jobs)
+	require.NoError(t, err)
+	require.Equal(t, "name  status age \nbuild OK     12  \ntest  \x1b[31mFAILED\x1b[0m 34", b.String())
+
+	b.Reset()
+	_, err = gfmt.NewTab(b, gfmt.WithHighlight(failed, old), gfmt.WithTableStyle(gfmt.StyleCompact)).Write(jobs)
+	require.NoError(t, err)
+	require.Equal(t, "name   status  age\n-----  ------  ---\nbuild  OK      12\n"+
+		"\x1b[33mtest\x1b[0m   \x1b[31mFAILED\x1b[0m  \x1b[33m34\x1b[0m", b.String())
+
+	b.Reset()
+	_, err = gfmt.NewTab(b, gfmt.WithHighlight(failed)).Write(jobs[1])
+	require.NoError(t, err)
+	require.Equal(t, "name   test   \nstatus \x1b[31mFAILED\x1b[0m \nage    34     \n", b.String())
+}
@@ -0,0 +1,181 @@
+// Copyright 2026 The gutenfmt authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gfmt
+
+import (
+	"errors"
+	"html/template"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/abc-inc/gutenfmt/formatter"
+	"github.com/abc-inc/gutenfmt/internal/render"
+	"github.com/abc-inc/gutenfmt/internal/table"
+)
+
+// htmlTemplate renders htmlNodes. All text is escaped by html/template.
+var htmlTemplate = template.Must(template.New("html").Parse(`
+{{- define "node" -}}
+{{- if .Table}}{{template "table" .Table}}
+{{- else if .List}}<ul>{{range .List}}<li>{{template "node" .}}</li>{{end}}</ul>
+{{- else}}{{.Text}}{{end -}}
+{{- end -}}
+
+{{- define "table" -}}
+<table>
+{{- if .Header}}
+<thead>
+<tr>{{range .Header}}<th>{{.}}</th>{{end}}</tr>
+</thead>
+{{- end}}
+<tbody>
+{{- $kv := .KeyValue}}
+{{- range .Rows}}
+<tr>{{range $idx, $c := .}}{{if and $kv (eq $idx 0)}}<th scope="row">{{template "node" $c}}</th>{{else}}<td>{{template "node" $c}}</td>{{end}}{{end}}</tr>
+{{- end}}
+</tbody>
+</table>
+{{- end -}}
+
+{{- define "page" -}}
+<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 0.25em 0.5em; text-align: left; vertical-align: top; }
+thead th { background: #eee; }
+ul { margin: 0; padding-left: 1.25em; }
+</style>
+</head>
+<body>
+{{template "node" .Node}}
+</body>
+</html>
+{{- end -}}
+`))
+
+// htmlNode is either a table, a list or text.
+type htmlNode struct {
+	Table *htmlTable
+	List  []htmlNode
+	Text  string
+}
+
+// htmlTable holds the cells of a (nested) table.
+type htmlTable struct {
+	Header   []string
+	Rows     [][]htmlNode
+	KeyValue bool
+}
+
+// HTML is a generic Writer that formats arbitrary values as HTML table.
+//
+// Slices of maps and structs are written as table with one row per element.
+// A single map or struct is written as table with one row per entry, each
+// consisting of name and value. Any other slice is written as list.
+// Nested values are written as nested tables or lists.
+type HTML struct {
+	writer    io.Writer
+	Formatter *formatter.CompFormatter
+	// Standalone writes a complete HTML page with embedded CSS instead of a fragment.
+	Standalone bool
+	// Title is the title of a standalone page.
+	Title string
+	// SortKeys sorts the keys of maps, which do not define an order.
+	SortKeys bool
+}
+
+// NewHTML creates a new HTML Writer.
+func NewHTML(w io.Writer, opts ...Opt[HTML]) *HTML {
+	gw := &HTML{writer: w, Formatter: formatter.NewComp()}
+	for _, opt := range opts {
+		opt(gw)
+	}
+	return gw
+}
+
+// Write writes the HTML representation of the given value to the underlying Writer.
+func (w HTML) Write(i any) (int, error) {
+	if i == nil {
+		return 0, nil
+	}
+
+	if s, err := w.Formatter.Format(i); err == nil {
+		return io.WriteString(w.writer, s)
+	} else if !errors.Is(err, formatter.ErrUnsupported) {
+		return 0, err
+	}
+
+	b := &strings.Builder{}
+	n := w.node(i)
+	var err error
+	if w.Standalone {
+		err = htmlTemplate.ExecuteTemplate(b, "page", struct {
+			Title string
+			Node  htmlNode
+		}{w.Title, n})
+	} else {
+		err = htmlTemplate.ExecuteTemplate(b, "node", n)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return io.WriteString(w.writer, b.String())
+}
+
+// node converts a value to an htmlNode, recursively.
+func (w HTML) node(i any) htmlNode {
+	t, ok := table.From(i, table.Options{SortKeys: w.SortKeys})
+	if !ok {
+		if v := reflect.ValueOf(i); v.Kind() == reflect.Ptr && !v.IsNil() {
+			return w.node(v.Elem().Interface())
+		}
+		return htmlNode{Text: render.ToString(i)}
+	}
+
+	if t.Header == nil && (len(t.Rows) == 0 || len(t.Rows[0]) == 1) {
+		if v := reflect.ValueOf(i); v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8 {
+			return htmlNode{Text: render.ToString(i)}
+		}
+		l := make([]htmlNode, len(t.Rows))
+		for idx, row := range t.Rows {
+			l[idx] = w.node(row[0])
+		}
+		return htmlNode{List: l}
+	}
+
+	ht := &htmlTable{Header: t.Header, KeyValue: t.Header == nil}
+	for _, row := range t.Rows {
+		r := make([]htmlNode, len(row))
+		for idx, c := range row {
+			r[idx] = w.node(c)
+		}
+		ht.Rows = append(ht.Rows, r)
+	}
+	return htmlNode{Table: ht}
+}
+
+// WithStandalone writes a complete HTML page with embedded CSS and the given title.
+func WithStandalone(title string) Opt[HTML] {
+	return func(w *HTML) {
+		w.Standalone = true
+		w.Title = title
+	}
+}
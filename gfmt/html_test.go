@@ -0,0 +1,76 @@
+// Copyright 2026 The gutenfmt authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gfmt_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/abc-inc/gutenfmt/gfmt"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTML_Write(t *testing.T) {
+	tests := []struct {
+		name string
+		arg  any
+		want string
+	}{
+		{"nil", nil, ""},
+		{"string", "<script>", "&lt;script&gt;"},
+		{"int_slice", []int{1, 2}, "<ul><li>1</li><li>2</li></ul>"},
+		{"struct", NewUser("John", "Doe"), heredoc.Doc(`
+			<table>
+			<tbody>
+			<tr><th scope="row">username</th><td>John Doe</td></tr>
+			<tr><th scope="row">email</th><td>john.doe@local</td></tr>
+			</tbody>
+			</table>`)},
+		{"map_slice", []any{newOrderedMap("a&b", "<i>", "c", newOrderedMap("d", []any{1, "e"}))}, heredoc.Doc(`
+			<table>
+			<thead>
+			<tr><th>a&amp;b</th><th>c</th></tr>
+			</thead>
+			<tbody>
+			<tr><td>&lt;i&gt;</td><td><table>
+			<tbody>
+			<tr><th scope="row">d</th><td><ul><li>1</li><li>e</li></ul></td></tr>
+			</tbody>
+			</table></td></tr>
+			</tbody>
+			</table>`)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &strings.Builder{}
+			_, err := gfmt.NewHTML(b).Write(tt.arg)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, b.String())
+		})
+	}
+}
+
+func TestHTML_WriteStandalone(t *testing.T) {
+	b := &strings.Builder{}
+	_, err := gfmt.NewHTML(b, gfmt.WithStandalone("<Report>")).Write([]User{*NewUser("John", "Doe")})
+	require.NoError(t, err)
+	require.Regexp(t, `^<!DOCTYPE html>`, b.String())
+	require.Contains(t, b.String(), "<title>&lt;Report&gt;</title>")
+	require.Contains(t, b.String(), "<style>")
+	require.Contains(t, b.String(), "<tr><td>John Doe</td><td>john.doe@local</td></tr>")
+	require.Regexp(t, `</html>$`, b.String())
+}
@@ -17,6 +17,7 @@ package gfmt
 import (
 	"reflect"
 
+	"github.com/abc-inc/gutenfmt/ordered"
 	"github.com/jmespath/go-jmespath"
 )
 
@@ -43,6 +44,17 @@ func (w JMESPath) Write(i any) (int, error) {
 		return 0, nil
 	}
 
+	// JMESPath does not know about ordered.Map, but the order of the maps, which
+	// are passed through unchanged, can be restored afterwards.
+	o := ordered.NewOrders()
+	if p := o.Plain(i); o.Len() > 0 {
+		v, err := w.Expr.Search(p)
+		if err != nil {
+			return 0, err
+		}
+		return w.writer.Write(o.Restore(v))
+	}
+
 	k := typ.Kind()
 	if k == reflect.Pointer {
 		return w.Write(reflect.ValueOf(i).Elem().Interface())
@@ -15,11 +15,13 @@
 package gfmt_test
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 	"testing"
 
 	"github.com/abc-inc/gutenfmt/gfmt"
+	"github.com/abc-inc/gutenfmt/ordered"
 	"github.com/stretchr/testify/require"
 )
 
@@ -65,3 +67,20 @@ func TestJMESPathWriter_WritePrimitive(t *testing.T) {
 		})
 	}
 }
+
+func TestJMESPathWriter_WriteOrderedMap(t *testing.T) {
+	b := &strings.Builder{}
+	w := gfmt.NewJMESPath(gfmt.NewJSON(b), "y")
+	_, err := w.Write(newOrderedMap("z", 1, "y", newOrderedMap("x", 2, "b", 3)))
+	require.NoError(t, err)
+	require.Equal(t, `{"x":2,"b":3}`, b.String())
+
+	for _, s := range []string{`{"b":{"a":1,"b":2}}`, `[{"b":1,"a":2},{"a":3,"b":4}]`} {
+		in, err := ordered.DecodeJSON(json.NewDecoder(strings.NewReader(s)))
+		require.NoError(t, err)
+		b.Reset()
+		_, err = gfmt.NewJMESPath(gfmt.NewJSON(b), "@").Write(in)
+		require.NoError(t, err)
+		require.Equal(t, s, b.String())
+	}
+}
@@ -16,16 +16,23 @@ package gfmt
 
 import (
 	"bytes"
+	"encoding"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"math"
+	"math/big"
 	"os"
 	"reflect"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 
+	"github.com/abc-inc/gutenfmt/meta"
+	"github.com/abc-inc/gutenfmt/ordered"
 	"github.com/itchyny/gojq"
 )
 
@@ -42,53 +49,177 @@ func NewArg(kv string, strLiteral bool) (*Arg, error) {
 	return nil, fmt.Errorf("invalid argument: %s", kv)
 }
 
+// ErrMultipleResults is the error resulting if a jq expression, which must
+// produce at most one result, produces more.
+var ErrMultipleResults = errors.New("jq expression produced multiple results")
+
+// Results determines how multiple results of a jq expression are passed to the delegate Writer.
+type Results int
+
+const (
+	// ResultsCollect passes multiple results to the delegate Writer as a single slice.
+	// If Raw is set and all results are scalar values, they are written one per line.
+	ResultsCollect Results = iota
+	// ResultsEach passes every result to the delegate Writer separately and
+	// writes a separator in between, like the jq command line tool does.
+	ResultsEach
+	// ResultsSingle fails with ErrMultipleResults, if there is more than one result.
+	ResultsSingle
+)
+
+// ParseResults converts "collect", "each" or "single" to the respective Results policy.
+func ParseResults(s string) (Results, error) {
+	switch strings.ToLower(s) {
+	case "collect", "":
+		return ResultsCollect, nil
+	case "each":
+		return ResultsEach, nil
+	case "single":
+		return ResultsSingle, nil
+	}
+	return ResultsCollect, fmt.Errorf("invalid jq results policy: %q", s)
+}
+
+// VarFile is a variable of a jq expression, whose value is read from a file.
+type VarFile struct {
+	// Name is the name of the variable with or without leading "$".
+	Name string
+	// Path is the name of the file.
+	Path string
+	// Raw sets the variable to the content of the file as string.
+	// Otherwise, it is set to an array of all JSON values in the file.
+	Raw bool
+}
+
+// DefaultModulePaths holds the module paths of the jq command line tool and the
+// project-local ".jq" file or directory in the current working directory.
+// "~" is expanded to the home directory and "$ORIGIN" to the directory of the executable.
+var DefaultModulePaths = []string{"~/.jq", ".jq", "$ORIGIN/../lib/jq", "$ORIGIN/../lib"}
+
+// JQ is a Writer that applies a jq filter to its input and passes the result to the delegate Writer.
 type JQ struct {
 	writer Writer
-	Expr   string
-	Args   []Arg
-	Raw    bool
+	out    io.Writer
+	code   *gojq.Code
+	vals   []any
+	inputs *jqInputs
+	orders *jqOrders
+	status *atomic.Int32
+	// Expr is the jq expression, which is compiled by the constructor.
+	Expr string
+	// Args holds the predefined variables of the jq expression.
+	Args []Arg
+	// Files holds the predefined variables of the jq expression, which are read from files.
+	Files []VarFile
+	// Positional holds the positional arguments, whose keys are ignored.
+	// They are available as $ARGS.positional and $__prog_args.
+	Positional []Arg
+	// Paths holds the directories, which are searched for modules imported or
+	// included by the expression (see DefaultModulePaths).
+	// A path named ".jq", which is a file, is loaded before the expression.
+	Paths []string
+	// Functions holds custom functions, which are available to the expression (see Builtins).
+	Functions []Function
+	// Raw writes string results directly instead of formatting them as JSON strings.
+	Raw bool
+	// Results determines how multiple results are passed to the delegate Writer.
+	Results Results
+	// Sep separates the results, if they are passed separately and there is an
+	// output for separators (see WithSeparator). Otherwise, a newline is written to the delegate Writer.
+	Sep string
+	// Seq writes an ASCII record separator before and a line feed after each result,
+	// which is passed separately (see RFC 7464).
+	Seq bool
+	// Slurp applies the expression once to an array of all inputs.
+	Slurp bool
+	// NullInput applies the expression once to null.
+	// The inputs are available via input and inputs.
+	NullInput bool
+	// Inputs enables input and inputs, which read the subsequent inputs.
+	// Writes are serialized, because the inputs are shared by all evaluations.
+	Inputs bool
+	// SortKeys keeps the keys of objects sorted instead of restoring the order
+	// of the input and the object constructions.
+	SortKeys bool
 }
 
-func NewJQ(delegate Writer, expr string, opts ...Opt[JQ]) *JQ {
+// jqOrders holds the key order of the objects of an evaluation.
+// Writes are serialized, because the order is recorded by a function of the compiled expression.
+type jqOrders struct {
+	mu sync.Mutex
+	o  *ordered.Orders
+}
+
+// keyOrderFunc is the name of the function, which records the key order of an
+// object construction (see rewriteObject).
+const keyOrderFunc = "_keyorder"
+
+// keyOrder records the key order of the object v, whose keys are given as array.
+func (o *jqOrders) keyOrder(v any, args []any) any {
+	m, ok := v.(map[string]any)
+	if !ok || o.o == nil {
+		return v
+	}
+	as, _ := args[0].([]any)
+	ks := make([]string, 0, len(as))
+	for _, a := range as {
+		if k, ok := a.(string); ok {
+			ks = append(ks, k)
+		}
+	}
+	o.o.Set(m, ks)
+	return v
+}
+
+// jqInputs is the iterator of the input and inputs functions.
+type jqInputs struct {
+	mu     sync.Mutex
+	vs     []any
+	next   int
+	orders *jqOrders
+}
+
+// Next returns the next input, which has not been consumed yet.
+func (it *jqInputs) Next() (any, bool) {
+	if it.next >= len(it.vs) {
+		return nil, false
+	}
+	v, err := normalize(reflect.ValueOf(it.vs[it.next]), it.orders.o)
+	it.next++
+	if err != nil {
+		return err, true
+	}
+	return v, true
+}
+
+// NewJQ creates a new Writer that applies the jq expression.
+// It returns an error, if the expression cannot be parsed or compiled.
+func NewJQ(delegate Writer, expr string, opts ...Opt[JQ]) (*JQ, error) {
 	return NewJQWithArgs(delegate, expr, nil, opts...)
 }
 
-func NewJQWithArgs(delegate Writer, expr string, args []Arg, opts ...Opt[JQ]) *JQ {
+// NewJQWithArgs creates a new Writer that applies the jq expression with predefined variables.
+// It returns an error, if the expression cannot be parsed or compiled.
+func NewJQWithArgs(delegate Writer, expr string, args []Arg, opts ...Opt[JQ]) (*JQ, error) {
 	w := &JQ{
 		writer: delegate,
+		orders: &jqOrders{},
+		status: &atomic.Int32{},
 		Expr:   expr,
 		Args:   args,
 	}
 	for _, opt := range opts {
 		opt(w)
 	}
-	return w
-}
-
-func (w JQ) Write(i any) (int, error) {
-	b := bytes.Buffer{}
-	if err := w.evalJQ(i, &b); err != nil {
-		return 0, err
-	}
-
-	// If the output is NO json, e.g., a literal string or null, write it as is.
-	if !json.Valid(b.Bytes()) || b.String() == "null\n" {
-		return w.writer.Write(strings.TrimSuffix(b.String(), "\n"))
+	w.status.Store(4)
+	if err := w.compile(); err != nil {
+		return nil, err
 	}
-
-	// Otherwise, create a new data structure and let the other writer handle it.
-	var v any
-	if err := json.Unmarshal(b.Bytes(), &v); err != nil {
-		return 0, err
-	} else if _, ok := v.(string); ok {
-		return w.writer.Write(strings.TrimSuffix(b.String(), "\n"))
-	}
-	return w.writer.Write(v)
+	return w, nil
 }
 
-// evalJQ evaluates a jq expression against an input and write it to an output.
-// Any top-level scalar values produced by the jq expression are written out as JSON scalars.
-func (w JQ) evalJQ(v any, out io.Writer) error {
+// compile parses and compiles the jq expression.
+func (w *JQ) compile() error {
 	query, err := gojq.Parse(w.Expr)
 	if err != nil {
 		var e *gojq.ParseError
@@ -102,77 +233,535 @@ func (w JQ) evalJQ(v any, out io.Writer) error {
 		return err
 	}
 
-	vars, vals, err := parseArgs(w.Args...)
+	fs := w.formats()
+	rewriteQuery(query, fs)
+
+	vars, vals, err := w.variables()
 	if err != nil {
 		return err
 	}
 
-	code, err := gojq.Compile(query,
+	opts := []gojq.CompilerOption{
 		gojq.WithEnvironLoader(os.Environ),
 		gojq.WithVariables(vars),
 		gojq.WithFunction("raw", 0, 0, rawFunc),
-	)
+		gojq.WithFunction(keyOrderFunc, 1, 1, w.orders.keyOrder),
+	}
+	for _, f := range w.Functions {
+		opts = append(opts, gojq.WithFunction(f.Name, f.MinArity, f.MaxArity, f.Call))
+	}
+	if len(w.Paths) > 0 {
+		opts = append(opts, gojq.WithModuleLoader(jqModuleLoader{gojq.NewModuleLoader(w.Paths), fs}))
+	}
+	if w.Inputs || w.NullInput {
+		w.inputs = &jqInputs{orders: w.orders}
+		opts = append(opts, gojq.WithInputIter(w.inputs))
+	}
+	w.code, err = gojq.Compile(query, opts...)
+	w.vals = vals
+	return err
+}
+
+// variables returns the names and values of all predefined variables including $ARGS and $__prog_args.
+func (w *JQ) variables() ([]string, []any, error) {
+	ks, vs, err := parseArgs(w.Args...)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 
-	// gojq panics upon errors involving third-party types like lists of a custom struct.
-	// As a workaround, we serialize the input to JSON and then deserialize it back.
-	var buf []byte
-	if buf, err = json.Marshal(v); err != nil {
+	for _, f := range w.Files {
+		b, err := os.ReadFile(f.Path)
+		if err != nil {
+			return nil, nil, err
+		}
+		var v any = string(b)
+		if !f.Raw {
+			if v, err = decodeAll(string(b)); err != nil {
+				return nil, nil, fmt.Errorf("invalid JSON in %s: %w", f.Path, err)
+			}
+		}
+		ks = append(ks, "$"+strings.TrimPrefix(f.Name, "$"))
+		vs = append(vs, v)
+	}
+
+	named := make(map[string]any, len(ks))
+	for idx, k := range ks {
+		named[k[1:]] = vs[idx]
+	}
+	pos := make([]any, len(w.Positional))
+	for idx, a := range w.Positional {
+		if pos[idx], err = argValue(a); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	ks = append(ks, "$ARGS", "$__prog_args")
+	vs = append(vs, map[string]any{"named": named, "positional": pos}, pos)
+	return ks, vs, nil
+}
+
+// Write applies the jq expression to i and writes the result to the delegate Writer.
+//
+// Strings are written as JSON strings, unless Raw is set, and null is written
+// as "null". Multiple results are handled according to Results.
+func (w JQ) Write(i any) (int, error) {
+	return w.WriteInputs([]any{i})
+}
+
+// WriteInputs applies the jq expression to each input like the jq command
+// line tool does, and writes the results of all inputs to the delegate Writer.
+//
+// If Slurp is set, the expression is applied to an array of all inputs.
+// If NullInput is set, the expression is applied to null and the inputs are
+// available via input and inputs.
+func (w JQ) WriteInputs(ins []any) (int, error) {
+	if w.inputs != nil {
+		w.inputs.mu.Lock()
+		defer w.inputs.mu.Unlock()
+		w.inputs.vs, w.inputs.next = ins, 0
+		if w.Slurp && w.NullInput {
+			w.inputs.vs = []any{ins}
+		}
+		defer func() { w.inputs.vs = nil }()
+	}
+	w.orders.mu.Lock()
+	defer w.orders.mu.Unlock()
+	if !w.SortKeys {
+		w.orders.o = ordered.NewOrders()
+		defer func() { w.orders.o = nil }()
+	}
+
+	var vs []any
+	// run applies the expression to i.
+	run := func(i any) error {
+		in, err := normalize(reflect.ValueOf(i), w.orders.o)
+		if err != nil {
+			return err
+		}
+		rs, err := w.eval(in)
+		vs = append(vs, w.restore(rs)...)
 		return err
 	}
-	if err = json.Unmarshal(buf, &v); err != nil {
+
+	// Like jq, the results produced before an error are still written.
+	evalErr := func() error {
+		switch {
+		case w.NullInput:
+			return run(nil)
+		case w.Slurp:
+			return run(ins)
+		case w.inputs != nil:
+			for w.inputs.next < len(w.inputs.vs) {
+				w.inputs.next++
+				i := w.inputs.vs[w.inputs.next-1]
+				if err := run(i); err != nil {
+					return err
+				}
+			}
+		default:
+			for _, i := range ins {
+				if err := run(i); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}()
+
+	if len(vs) > 0 {
+		if last := vs[len(vs)-1]; last == nil || last == false {
+			w.status.Store(1)
+		} else {
+			w.status.Store(0)
+		}
+	}
+
+	n, err := w.writeResults(vs)
+	if evalErr != nil {
+		return n, evalErr
+	}
+	return n, err
+}
+
+// writeResults writes the results to the delegate Writer according to Results.
+func (w JQ) writeResults(vs []any) (int, error) {
+	switch {
+	case len(vs) == 0:
+		return 0, nil
+	case w.Results == ResultsEach || w.Seq:
+		return w.writeEach(vs)
+	case len(vs) == 1:
+		return w.write(vs[0])
+	case w.Results == ResultsSingle:
+		return 0, fmt.Errorf("%w: %d", ErrMultipleResults, len(vs))
+	case w.Raw && scalars(vs):
+		ls := make([]string, len(vs))
+		for idx, v := range vs {
+			s, err := w.text(v)
+			if err != nil {
+				return 0, err
+			}
+			ls[idx] = s
+		}
+		return w.writer.Write(strings.Join(ls, "\n"))
+	default:
+		return w.writer.Write(vs)
+	}
+}
+
+// scalars returns true if none of the results is an object or an array.
+func scalars(vs []any) bool {
+	for _, v := range vs {
+		switch v.(type) {
+		case map[string]any, *ordered.Map, []any:
+			return false
+		}
+	}
+	return true
+}
+
+// writeEach writes every result separately to the delegate Writer.
+// Without output for separators, the results are separated by newlines written to the delegate.
+func (w JQ) writeEach(vs []any) (int, error) {
+	cnt := 0
+	str := func(s string) error {
+		if s == "" {
+			return nil
+		}
+		var n int
+		var err error
+		if w.out != nil {
+			n, err = io.WriteString(w.out, s)
+		} else {
+			n, err = w.writer.Write(s)
+		}
+		cnt += n
 		return err
 	}
 
-	iter := code.Run(v, vals...)
+	for idx, v := range vs {
+		if idx > 0 && !w.Seq {
+			sep := w.Sep
+			if w.out == nil {
+				sep = "\n"
+			}
+			if err := str(sep); err != nil {
+				return cnt, err
+			}
+		} else if w.Seq {
+			if err := str("\x1e"); err != nil {
+				return cnt, err
+			}
+		}
+		n, err := w.write(v)
+		cnt += n
+		if err != nil {
+			return cnt, err
+		}
+		if w.Seq {
+			if err := str("\n"); err != nil {
+				return cnt, err
+			}
+		}
+	}
+	return cnt, nil
+}
+
+// ExitStatus returns the exit status of the jq command line tool with the
+// option --exit-status i.e., 0 if the last result was neither false nor null,
+// 1 if it was false or null, or 4 if there was no result at all.
+func (w JQ) ExitStatus() int {
+	return int(w.status.Load())
+}
+
+// write writes a single result to the delegate Writer.
+func (w JQ) write(v any) (int, error) {
+	switch v.(type) {
+	case nil, string:
+		s, err := w.text(v)
+		if err != nil {
+			return 0, err
+		}
+		return w.writer.Write(s)
+	}
+	return w.writer.Write(v)
+}
+
+// restore restores the key order of the objects in the results vs.
+//
+// gojq sorts the keys of objects. Hence, the order of the input objects, which
+// are passed through unchanged, and the order of the object constructions is restored.
+// If SortKeys is set, the keys are kept sorted for delegates, which do not sort maps.
+func (w JQ) restore(vs []any) []any {
+	if o := w.orders.o; w.SortKeys || o.Len() > 0 {
+		for idx, v := range vs {
+			vs[idx] = o.Restore(v)
+		}
+	}
+	return vs
+}
+
+// rewriteObject replaces an object construction by a query, which records the
+// key order of the object. Keys, which are not known before evaluation, are
+// sorted after the others.
+func rewriteObject(t *gojq.Term) {
+	var ks *gojq.Query
+	for _, kv := range t.Object.KeyVals {
+		var k string
+		switch {
+		case strings.HasPrefix(kv.Key, "$") && kv.Val == nil:
+			k = kv.Key[1:]
+		case kv.Key != "" && !strings.ContainsAny(kv.Key[:1], "$@"):
+			k = kv.Key
+		case kv.KeyString != nil && kv.KeyString.Queries == nil:
+			k = kv.KeyString.Str
+		default:
+			continue
+		}
+		q := &gojq.Query{Term: &gojq.Term{Type: gojq.TermTypeString, Str: &gojq.String{Str: k}}}
+		if ks != nil {
+			q = &gojq.Query{Left: ks, Op: gojq.OpComma, Right: q}
+		}
+		ks = q
+	}
+	if ks == nil {
+		return
+	}
+
+	obj := &gojq.Query{Term: &gojq.Term{Type: gojq.TermTypeObject, Object: t.Object}}
+	f := &gojq.Query{Term: &gojq.Term{Type: gojq.TermTypeFunc, Func: &gojq.Func{Name: keyOrderFunc,
+		Args: []*gojq.Query{{Term: &gojq.Term{Type: gojq.TermTypeArray, Array: &gojq.Array{Query: ks}}}}}}}
+	t.Type, t.Object, t.Query = gojq.TermTypeQuery, nil, &gojq.Query{Left: obj, Op: gojq.OpPipe, Right: f}
+}
+
+// eval evaluates the compiled jq expression against an input and returns all results.
+// If an error occurs, the results produced before are returned along with it.
+func (w JQ) eval(in any) ([]any, error) {
+	var vs []any
+	iter := w.code.Run(in, w.vals...)
 	for {
-		val, hasNext := iter.Next()
+		v, hasNext := iter.Next()
 		if !hasNext {
 			break
 		}
-		if vErr, isErr := val.(error); isErr {
+		if vErr, isErr := v.(error); isErr {
 			var e *gojq.HaltError
 			if errors.As(vErr, &e) && e.Value() == nil {
 				break
 			}
-			return vErr
+			return vs, vErr
 		}
+		vs = append(vs, v)
+	}
+	return vs, nil
+}
 
-		var j []byte
-		if typ := reflect.TypeOf(val); typ != nil && reflect.TypeOf(val).Kind() == reflect.String && w.Raw {
-			j = []byte(val.(string))
-		} else {
-			if j, err = json.Marshal(val); err != nil {
-				return err
+// text returns the JSON representation of a result, or the string itself if Raw is set.
+func (w JQ) text(v any) (string, error) {
+	if s, ok := v.(string); ok && w.Raw {
+		return s, nil
+	}
+	b := &bytes.Buffer{}
+	enc := json.NewEncoder(b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(b.String(), "\n"), nil
+}
+
+// normalize converts a Go value to the types gojq operates on i.e., nil, bool,
+// int, float64, *big.Int, string, []any and map[string]any.
+//
+// Struct fields are named as determined by meta.Resolve, and the fields of
+// embedded structs without name are promoted like encoding/json does.
+// Types implementing json.Marshaler or encoding.TextMarshaler are converted
+// using their JSON or text representation.
+func normalize(v reflect.Value, o *ordered.Orders) (any, error) {
+	if !v.IsValid() {
+		return nil, nil
+	}
+
+	switch x := v.Interface().(type) {
+	case ordered.Map:
+		return normalizeMap(&x, o)
+	case *ordered.Map:
+		if x == nil {
+			return nil, nil
+		}
+		return normalizeMap(x, o)
+	case json.Number:
+		return x, nil
+	case json.Marshaler:
+		if v.Kind() == reflect.Ptr && v.IsNil() {
+			return nil, nil
+		}
+		b, err := x.MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+		return decode(string(b))
+	case encoding.TextMarshaler:
+		if v.Kind() == reflect.Ptr && v.IsNil() {
+			return nil, nil
+		}
+		b, err := x.MarshalText()
+		return string(b), err
+	}
+
+	switch v.Kind() { //nolint:exhaustive
+	case reflect.Bool:
+		return v.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if n := v.Int(); n >= math.MinInt && n <= math.MaxInt {
+			return int(n), nil
+		}
+		return big.NewInt(v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if n := v.Uint(); n <= math.MaxInt {
+			return int(n), nil
+		}
+		return new(big.Int).SetUint64(v.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), nil
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil, nil
+		}
+		return normalize(v.Elem(), o)
+	case reflect.Slice:
+		if v.IsNil() {
+			return nil, nil
+		} else if v.Type().Elem().Kind() == reflect.Uint8 {
+			return base64.StdEncoding.EncodeToString(v.Bytes()), nil
+		}
+		return normalizeSlice(v, o)
+	case reflect.Array:
+		return normalizeSlice(v, o)
+	case reflect.Map:
+		if v.IsNil() {
+			return nil, nil
+		}
+		m := make(map[string]any, v.Len())
+		for it := v.MapRange(); it.Next(); {
+			k, err := normalizeKey(it.Key())
+			if err != nil {
+				return nil, err
+			}
+			if m[k], err = normalize(it.Value(), o); err != nil {
+				return nil, err
 			}
 		}
-		if _, err = fmt.Fprintln(out, string(j)); err != nil {
+		return m, nil
+	case reflect.Struct:
+		m := map[string]any{}
+		return m, normalizeStruct(v, m, o)
+	}
+	return nil, fmt.Errorf("unsupported type: %s", v.Type())
+}
+
+// normalizeMap converts an ordered.Map to a map[string]any and records its key order in o.
+func normalizeMap(om *ordered.Map, o *ordered.Orders) (any, error) {
+	m := make(map[string]any, om.Len())
+	for _, k := range om.Keys() {
+		e, _ := om.Get(k)
+		var err error
+		if m[k], err = normalize(reflect.ValueOf(e), o); err != nil {
+			return nil, err
+		}
+	}
+	o.Set(m, om.Keys())
+	return m, nil
+}
+
+// normalizeSlice converts a slice or an array to a []any.
+func normalizeSlice(v reflect.Value, o *ordered.Orders) (any, error) {
+	s := make([]any, v.Len())
+	for idx := range s {
+		var err error
+		if s[idx], err = normalize(v.Index(idx), o); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// normalizeStruct adds the fields of a struct to m.
+// Fields of embedded structs are added, unless m already holds a field of the same name.
+func normalizeStruct(v reflect.Value, m map[string]any, o *ordered.Orders) error {
+	for _, f := range meta.Resolve(v.Type()) {
+		e, err := normalize(v.FieldByName(f.Field), o)
+		if err != nil {
 			return err
 		}
+		m[f.Name] = e
 	}
 
+	for idx := 0; idx < v.NumField(); idx++ {
+		sf := v.Type().Field(idx)
+		if _, ok := m[sf.Name]; ok || !sf.Anonymous {
+			continue
+		}
+		f := reflect.Indirect(v.Field(idx))
+		if f.Kind() != reflect.Struct {
+			continue
+		}
+		em := map[string]any{}
+		if err := normalizeStruct(f, em, o); err != nil {
+			return err
+		}
+		for k, e := range em {
+			if _, ok := m[k]; !ok {
+				m[k] = e
+			}
+		}
+	}
 	return nil
 }
 
+// normalizeKey converts a map key to a string like encoding/json does.
+func normalizeKey(k reflect.Value) (string, error) {
+	if tm, ok := k.Interface().(encoding.TextMarshaler); ok {
+		b, err := tm.MarshalText()
+		return string(b), err
+	}
+	switch k.Kind() { //nolint:exhaustive
+	case reflect.Interface:
+		if !k.IsNil() {
+			return normalizeKey(k.Elem())
+		}
+	case reflect.String:
+		return k.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(k.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(k.Uint(), 10), nil
+	}
+	return "", fmt.Errorf("unsupported map key type: %s", k.Type())
+}
+
 func parseArgs(args ...Arg) (ks []string, vs []any, err error) {
 	for _, a := range args {
-		if a.String {
-			ks = append(ks, "$"+strings.TrimPrefix(a.Key, "$"))
-			vs = append(vs, a.Val)
-		} else {
-			var j any
-			if j, err = decode(a.Val); err != nil {
-				return nil, nil, err
-			}
-			ks = append(ks, "$"+strings.TrimPrefix(a.Key, "$"))
-			vs = append(vs, j)
+		var v any
+		if v, err = argValue(a); err != nil {
+			return nil, nil, err
 		}
+		ks = append(ks, "$"+strings.TrimPrefix(a.Key, "$"))
+		vs = append(vs, v)
 	}
 	return
 }
 
+// argValue returns the value of an argument, which is either a string or JSON.
+func argValue(a Arg) (any, error) {
+	if a.String {
+		return a.Val, nil
+	}
+	return decode(a.Val)
+}
+
 func jsonScalarToString(input interface{}) (string, bool) { //nolint:unused
 	switch tt := input.(type) {
 	case string:
@@ -221,8 +810,91 @@ func decode(s string) (v any, err error) {
 	return
 }
 
+// decodeAll decodes all JSON values in s into an array.
+func decodeAll(s string) ([]any, error) {
+	vs := []any{}
+	dec := json.NewDecoder(strings.NewReader(s))
+	dec.UseNumber()
+	for {
+		var v any
+		if err := dec.Decode(&v); errors.Is(err, io.EOF) {
+			return vs, nil
+		} else if err != nil {
+			return nil, err
+		}
+		vs = append(vs, v)
+	}
+}
+
 func WithRaw() Opt[JQ] {
 	return func(w *JQ) {
 		w.Raw = true
 	}
 }
+
+// WithResults determines how multiple results of a JQ Writer are passed to the delegate Writer.
+func WithResults(r Results) Opt[JQ] {
+	return func(w *JQ) {
+		w.Results = r
+	}
+}
+
+// WithSeparator passes every result of a JQ Writer separately to the delegate
+// Writer and writes sep to out in between.
+func WithSeparator(out io.Writer, sep string) Opt[JQ] {
+	return func(w *JQ) {
+		w.Results, w.out, w.Sep = ResultsEach, out, sep
+	}
+}
+
+// WithSeq passes every result of a JQ Writer separately to the delegate Writer
+// and writes an ASCII record separator before and a line feed after it to out.
+func WithSeq(out io.Writer) Opt[JQ] {
+	return func(w *JQ) {
+		w.Results, w.out, w.Seq = ResultsEach, out, true
+	}
+}
+
+// WithSlurp applies the expression of a JQ Writer once to an array of all inputs.
+func WithSlurp() Opt[JQ] {
+	return func(w *JQ) {
+		w.Slurp = true
+	}
+}
+
+// WithNullInput applies the expression of a JQ Writer once to null.
+// The inputs are available via input and inputs.
+func WithNullInput() Opt[JQ] {
+	return func(w *JQ) {
+		w.NullInput = true
+	}
+}
+
+// WithInputs enables input and inputs for a JQ Writer.
+func WithInputs() Opt[JQ] {
+	return func(w *JQ) {
+		w.Inputs = true
+	}
+}
+
+// WithVarFile adds a variable to a JQ Writer, whose value is read from a file.
+func WithVarFile(f VarFile) Opt[JQ] {
+	return func(w *JQ) {
+		w.Files = append(w.Files, f)
+	}
+}
+
+// WithPositional adds positional arguments to a JQ Writer.
+func WithPositional(args ...Arg) Opt[JQ] {
+	return func(w *JQ) {
+		w.Positional = append(w.Positional, args...)
+	}
+}
+
+// WithModulePaths adds directories to a JQ Writer, which are searched for modules.
+// Paths named ".jq", which are files, define functions available to the expression.
+func WithModulePaths(paths ...string) Opt[JQ] {
+	return func(w *JQ) {
+		w.Paths = append(w.Paths, paths...)
+	}
+}
@@ -15,11 +15,18 @@
 package gfmt_test
 
 import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/MakeNowJust/heredoc/v2"
 	"github.com/abc-inc/gutenfmt/gfmt"
+	"github.com/abc-inc/gutenfmt/ordered"
 	"github.com/alecthomas/chroma/v2/styles"
 	"github.com/stretchr/testify/require"
 )
@@ -33,27 +40,35 @@ func TestJQWriter_Write(t *testing.T) {
 	  e: 4`)
 
 	b := strings.Builder{}
-	w := gfmt.NewJQ(gfmt.NewYAML(&b), ".b")
-	_, err := w.Write(map[string]any{"a": 1, "b": map[string]any{"c": []string{"c", "c"}, "d": map[string]any{"e": 4}}})
+	w, err := gfmt.NewJQ(gfmt.NewYAML(&b), ".b")
+	require.NoError(t, err)
+	_, err = w.Write(map[string]any{"a": 1, "b": map[string]any{"c": []string{"c", "c"}, "d": map[string]any{"e": 4}}})
 	require.NoError(t, err)
 	require.Equal(t, exp, b.String())
 }
 
 func TestJQWriter_WritePretty(t *testing.T) {
+	write := func(w gfmt.Writer, expr string, i any) {
+		jq, err := gfmt.NewJQ(w, expr)
+		require.NoError(t, err)
+		_, err = jq.Write(i)
+		require.NoError(t, err)
+	}
+
 	b := strings.Builder{}
-	_, _ = gfmt.NewJQ(gfmt.NewJSON(&b, gfmt.WithPretty[gfmt.JSON](), gfmt.WithStyle[gfmt.JSON](styles.Fallback)), ".").Write([]string{"A"})
+	write(gfmt.NewJSON(&b, gfmt.WithPretty[gfmt.JSON](), gfmt.WithStyle[gfmt.JSON](styles.Fallback)), ".", []string{"A"})
 	require.Regexp(t, `^\[\n  `, b.String())
 
 	b = strings.Builder{}
-	_, _ = gfmt.NewJQ(gfmt.NewJSON(&b, gfmt.WithPretty[gfmt.JSON](), gfmt.WithStyle[gfmt.JSON](styles.Fallback)), ".nestedNumber").Write(map[string]any{"nestedNumber": "5"})
+	write(gfmt.NewJSON(&b, gfmt.WithPretty[gfmt.JSON](), gfmt.WithStyle[gfmt.JSON](styles.Fallback)), ".nestedNumber", map[string]any{"nestedNumber": "5"})
 	require.Regexp(t, `5`, b.String())
 
 	b = strings.Builder{}
-	_, _ = gfmt.NewJQ(gfmt.NewJSON(&b, gfmt.WithPretty[gfmt.JSON](), gfmt.WithStyle[gfmt.JSON](styles.Fallback)), ".").Write(`a5`)
+	write(gfmt.NewJSON(&b, gfmt.WithPretty[gfmt.JSON](), gfmt.WithStyle[gfmt.JSON](styles.Fallback)), ".", `a5`)
 	require.Regexp(t, `a5`, b.String())
 
 	b = strings.Builder{}
-	_, _ = gfmt.NewJQ(gfmt.WrapIOWriter(&b), ".nestedString").Write(map[string]any{"nestedString": "a5"})
+	write(gfmt.WrapIOWriter(&b), ".nestedString", map[string]any{"nestedString": "a5"})
 	require.Regexp(t, `a5`, b.String())
 }
 
@@ -74,8 +89,9 @@ func TestJQWriter_WriteAny(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			b := &strings.Builder{}
-			w := gfmt.NewJQ(gfmt.WrapIOWriter(b), tt.expr)
-			_, err := w.Write(tt.input)
+			w, err := gfmt.NewJQ(gfmt.WrapIOWriter(b), tt.expr)
+			require.NoError(t, err)
+			_, err = w.Write(tt.input)
 			if tt.expected == "" {
 				require.Error(t, err)
 			} else {
@@ -85,3 +101,344 @@ func TestJQWriter_WriteAny(t *testing.T) {
 		})
 	}
 }
+
+func TestJQWriter_WriteOrderedMap(t *testing.T) {
+	b := &strings.Builder{}
+	in := []any{newOrderedMap("z", 1, "y", newOrderedMap("x", 2, "b", 3)), newOrderedMap("a", 4)}
+	w, err := gfmt.NewJQ(gfmt.NewJSON(b), "map(. + {new: 0})")
+	require.NoError(t, err)
+	_, err = w.Write(in)
+	require.NoError(t, err)
+	// Modified objects are sorted, but unchanged ones keep their order.
+	require.Equal(t, `[{"new":0,"y":{"x":2,"b":3},"z":1},{"a":4,"new":0}]`, b.String())
+
+	for expr, want := range map[string]string{
+		"{b: .b, a: .a}": `{"b":2,"a":1}`,
+		`4 as $v | {z: 1, ("y"): 2, "x": 3, $v, a}`: `{"z":1,"x":3,"v":4,"a":1,"y":2}`,
+		"{b: .b, a: .a} | .c = 3":                   `{"a":1,"b":2,"c":3}`,
+		"[{b: .b}, .]":                              `[{"b":2},{"a":1,"b":2}]`,
+	} {
+		b.Reset()
+		w, err := gfmt.NewJQ(gfmt.NewJSON(b), expr)
+		require.NoError(t, err)
+		_, err = w.Write(map[string]any{"a": 1, "b": 2})
+		require.NoError(t, err)
+		require.Equal(t, want, b.String(), expr)
+	}
+
+	for _, s := range []string{`{"b":{"a":1,"b":2}}`, `[{"b":1,"a":2},{"a":3,"b":4}]`} {
+		in, err := ordered.DecodeJSON(json.NewDecoder(strings.NewReader(s)))
+		require.NoError(t, err)
+		b.Reset()
+		w, err := gfmt.NewJQ(gfmt.NewJSON(b), ".")
+		require.NoError(t, err)
+		_, err = w.Write(in)
+		require.NoError(t, err)
+		require.Equal(t, s, b.String())
+	}
+}
+
+func TestNewJQ_Error(t *testing.T) {
+	_, err := gfmt.NewJQ(gfmt.NewJSON(io.Discard), ".a |")
+	require.ErrorContains(t, err, "failed to parse jq expression (line 1, column 5)")
+
+	_, err = gfmt.NewJQ(gfmt.NewJSON(io.Discard), "undefined_func")
+	require.ErrorContains(t, err, "function not defined: undefined_func/0")
+
+	_, err = gfmt.NewJQWithArgs(gfmt.NewJSON(io.Discard), ".", []gfmt.Arg{{Key: "x", Val: "{"}})
+	require.Error(t, err)
+}
+
+func TestJQWriter_WriteStruct(t *testing.T) {
+	type Base struct {
+		ID int `json:"id"`
+	}
+	type item struct {
+		Base
+		Name    string            `json:"name"`
+		Created time.Time         `json:"created"`
+		Tags    map[int]string    `json:"tags"`
+		Data    []byte            `json:"data"`
+		Skip    string            `json:"-"`
+		Attrs   map[string]any    `json:"attrs"`
+		Nested  *ordered.Map      `json:"nested"`
+		Nil     *time.Time        `json:"nil"`
+		Labels  map[string]string `json:"labels,omitempty"`
+	}
+
+	in := []item{{
+		Base:    Base{ID: 7},
+		Name:    "x",
+		Created: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		Tags:    map[int]string{1: "a"},
+		Data:    []byte("hi"),
+		Skip:    "skip",
+		Attrs:   map[string]any{"n": json.Number("1.5"), "u": uint8(3)},
+		Nested:  newOrderedMap("z", 1, "a", 2),
+	}}
+
+	b := &strings.Builder{}
+	w, err := gfmt.NewJQ(gfmt.NewJSON(b), ".[0] | .attrs.n += 1 | .nested.z += 1")
+	require.NoError(t, err)
+	_, err = w.Write(in)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"id":7,"name":"x","created":"2026-01-02T03:04:05Z","tags":{"1":"a"},"data":"aGk=",`+
+		`"attrs":{"n":2.5,"u":3},"nested":{"z":2,"a":2},"nil":null,"labels":null}`, b.String())
+	require.Equal(t, json.Number("1.5"), in[0].Attrs["n"])
+}
+
+func TestJQWriter_WriteMultiple(t *testing.T) {
+	in := []any{newOrderedMap("name", "Jo", "age", 7), newOrderedMap("name", "Al", "age", 42)}
+
+	b := &strings.Builder{}
+	w, err := gfmt.NewJQ(gfmt.NewTab(b), ".[] | {name, age}")
+	require.NoError(t, err)
+	_, err = w.Write(in)
+	require.NoError(t, err)
+	require.Equal(t, "name age \nJo   7   \nAl   42", b.String())
+
+	// A single result is passed as is.
+	b.Reset()
+	_, err = w.Write(in[:1])
+	require.NoError(t, err)
+	require.Equal(t, "name Jo  \nage  7   \n", b.String())
+
+	b.Reset()
+	w, err = gfmt.NewJQ(gfmt.NewJSON(b), ".[] | .name", gfmt.WithResults(gfmt.ResultsEach))
+	require.NoError(t, err)
+	_, err = w.Write(in)
+	require.NoError(t, err)
+	require.Equal(t, "\"Jo\"\n\"Al\"", b.String())
+
+	b.Reset()
+	w, err = gfmt.NewJQ(gfmt.NewJSON(b), ".[] | .name", gfmt.WithResults(gfmt.ResultsCollect))
+	require.NoError(t, err)
+	_, err = w.Write(in)
+	require.NoError(t, err)
+	require.Equal(t, `["Jo","Al"]`, b.String())
+
+	b.Reset()
+	w, err = gfmt.NewJQ(gfmt.NewJSON(b), ".[] | .name, .age", gfmt.WithResults(gfmt.ResultsCollect), gfmt.WithRaw())
+	require.NoError(t, err)
+	_, err = w.Write(in)
+	require.NoError(t, err)
+	require.Equal(t, "Jo\n7\nAl\n42", b.String())
+
+	b.Reset()
+	w, err = gfmt.NewJQ(gfmt.NewJSON(b), ".[] | .name", gfmt.WithSeparator(b, "\n"))
+	require.NoError(t, err)
+	_, err = w.Write(in)
+	require.NoError(t, err)
+	require.Equal(t, "\"Jo\"\n\"Al\"", b.String())
+
+	w, err = gfmt.NewJQ(gfmt.NewJSON(b), ".[]", gfmt.WithResults(gfmt.ResultsSingle))
+	require.NoError(t, err)
+	_, err = w.Write(in)
+	require.ErrorIs(t, err, gfmt.ErrMultipleResults)
+
+	b.Reset()
+	_, err = w.Write([]any{})
+	require.NoError(t, err)
+	require.Equal(t, "", b.String())
+}
+
+func TestParseResults(t *testing.T) {
+	r, err := gfmt.ParseResults("Each")
+	require.NoError(t, err)
+	require.Equal(t, gfmt.ResultsEach, r)
+
+	_, err = gfmt.ParseResults("all")
+	require.Error(t, err)
+}
+
+func TestJQWriter_WriteUnsupported(t *testing.T) {
+	w, err := gfmt.NewJQ(gfmt.NewJSON(io.Discard), ".")
+	require.NoError(t, err)
+	_, err = w.Write(map[string]any{"c": make(chan int)})
+	require.ErrorContains(t, err, "unsupported type: chan int")
+}
+
+func benchmarkInput() []*User {
+	us := make([]*User, 1000)
+	for idx := range us {
+		us[idx] = NewUser("John"+strconv.Itoa(idx), "Doe")
+	}
+	return us
+}
+
+func BenchmarkJQ_Write(b *testing.B) {
+	in := benchmarkInput()
+	w, err := gfmt.NewJQ(gfmt.NewJSON(io.Discard), "map(select(.username | endswith(\"9 Doe\")))")
+	require.NoError(b, err)
+
+	b.ResetTimer()
+	for range b.N {
+		if _, err := w.Write(in); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkJQ_WriteRoundTrip compiles the expression for every input and
+// converts the input via JSON, for comparison with BenchmarkJQ_Write.
+func BenchmarkJQ_WriteRoundTrip(b *testing.B) {
+	in := benchmarkInput()
+
+	for range b.N {
+		w, err := gfmt.NewJQ(gfmt.NewJSON(io.Discard), "map(select(.username | endswith(\"9 Doe\")))")
+		if err != nil {
+			b.Fatal(err)
+		}
+		j, err := json.Marshal(in)
+		if err != nil {
+			b.Fatal(err)
+		}
+		var v any
+		if err = json.Unmarshal(j, &v); err != nil {
+			b.Fatal(err)
+		}
+		if _, err = w.Write(v); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestJQWriter_WriteInputs(t *testing.T) {
+	docs := []any{newOrderedMap("n", 1), newOrderedMap("n", 2), newOrderedMap("n", 3)}
+	tests := []struct {
+		name string
+		expr string
+		opts []gfmt.Opt[gfmt.JQ]
+		want string
+	}{
+		{"each", ".n", []gfmt.Opt[gfmt.JQ]{gfmt.WithSeparator(io.Discard, "")}, "123"},
+		{"collect", ".n", []gfmt.Opt[gfmt.JQ]{gfmt.WithResults(gfmt.ResultsCollect)}, "[1,2,3]"},
+		{"slurp", "map(.n) | add", []gfmt.Opt[gfmt.JQ]{gfmt.WithSlurp()}, "6"},
+		{"null_input", "[inputs.n]", []gfmt.Opt[gfmt.JQ]{gfmt.WithNullInput()}, "[1,2,3]"},
+		{"null_input_slurp", "input | length", []gfmt.Opt[gfmt.JQ]{gfmt.WithNullInput(), gfmt.WithSlurp()}, "3"},
+		{"inputs", "[.n, inputs.n]", []gfmt.Opt[gfmt.JQ]{gfmt.WithInputs()}, "[1,2,3]"},
+		{"sort_keys", "{z: 1, a: .[0].n}", []gfmt.Opt[gfmt.JQ]{gfmt.WithSortKeys[gfmt.JQ](), gfmt.WithSlurp()}, `{"a":1,"z":1}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &strings.Builder{}
+			w, err := gfmt.NewJQ(gfmt.NewJSON(b), tt.expr, tt.opts...)
+			require.NoError(t, err)
+			_, err = w.WriteInputs(docs)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, b.String())
+		})
+	}
+}
+
+func TestJQWriter_WriteInputsError(t *testing.T) {
+	b := &strings.Builder{}
+	w, err := gfmt.NewJQ(gfmt.NewJSON(b), ".a + 1")
+	require.NoError(t, err)
+	_, err = w.WriteInputs([]any{newOrderedMap("a", 1), newOrderedMap("a", "x"), newOrderedMap("a", 3)})
+	require.ErrorContains(t, err, "cannot add")
+	require.Equal(t, "2", b.String())
+}
+
+func TestJQWriter_WriteInputsConsumed(t *testing.T) {
+	b := &strings.Builder{}
+	w, err := gfmt.NewJQ(gfmt.NewJSON(b), "[., input]", gfmt.WithInputs(), gfmt.WithResults(gfmt.ResultsCollect))
+	require.NoError(t, err)
+	_, err = w.WriteInputs([]any{1, 2, 3, 4})
+	require.NoError(t, err)
+	require.Equal(t, "[[1,2],[3,4]]", b.String())
+
+	_, err = gfmt.NewJQ(gfmt.NewJSON(b), "input")
+	require.ErrorContains(t, err, "input(s)/0 is not allowed")
+}
+
+func TestJQWriter_WriteSeq(t *testing.T) {
+	b := &strings.Builder{}
+	w, err := gfmt.NewJQ(gfmt.NewJSON(b), ".[]", gfmt.WithSeq(b))
+	require.NoError(t, err)
+	_, err = w.Write([]any{1, "a"})
+	require.NoError(t, err)
+	require.Equal(t, "\x1e1\n\x1e\"a\"\n", b.String())
+
+	b.Reset()
+	w, err = gfmt.NewJQ(gfmt.WrapIOWriter(b), ".[]", gfmt.WithRaw(), gfmt.WithSeparator(b, ""))
+	require.NoError(t, err)
+	_, err = w.Write([]any{"a", "b", 1})
+	require.NoError(t, err)
+	require.Equal(t, "ab1", b.String())
+}
+
+func TestJQWriter_ExitStatus(t *testing.T) {
+	w, err := gfmt.NewJQ(gfmt.NewJSON(io.Discard), ".[]")
+	require.NoError(t, err)
+	require.Equal(t, 4, w.ExitStatus())
+
+	_, err = w.Write([]any{})
+	require.NoError(t, err)
+	require.Equal(t, 4, w.ExitStatus())
+
+	_, err = w.Write([]any{1, false})
+	require.NoError(t, err)
+	require.Equal(t, 1, w.ExitStatus())
+
+	_, err = w.Write([]any{nil, "x"})
+	require.NoError(t, err)
+	require.Equal(t, 0, w.ExitStatus())
+}
+
+func TestJQWriter_WriteVariables(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.json"), []byte(`{"a":1} 2`), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "b.txt"), []byte("text\n"), 0o600))
+
+	b := &strings.Builder{}
+	w, err := gfmt.NewJQWithArgs(gfmt.NewJSON(b), "[$s, $r, $ARGS, $__prog_args]", []gfmt.Arg{{Key: "x", Val: "y", String: true}},
+		gfmt.WithVarFile(gfmt.VarFile{Name: "s", Path: filepath.Join(dir, "a.json")}),
+		gfmt.WithVarFile(gfmt.VarFile{Name: "$r", Path: filepath.Join(dir, "b.txt"), Raw: true}),
+		gfmt.WithPositional(gfmt.Arg{Val: "p", String: true}, gfmt.Arg{Val: `{"q":true}`}, gfmt.Arg{Val: "1", String: true}))
+	require.NoError(t, err)
+	_, err = w.Write(nil)
+	require.NoError(t, err)
+	require.JSONEq(t, `[[{"a":1},2],"text\n",`+
+		`{"named":{"x":"y","s":[{"a":1},2],"r":"text\n"},"positional":["p",{"q":true},"1"]},["p",{"q":true},"1"]]`, b.String())
+
+	_, err = gfmt.NewJQ(gfmt.NewJSON(b), ".", gfmt.WithVarFile(gfmt.VarFile{Name: "s", Path: filepath.Join(dir, "missing")}))
+	require.Error(t, err)
+}
+
+func TestJQWriter_WriteModules(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "lib"), 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "lib", "math.jq"), []byte("def inc: . + 1;"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "lib", "data.json"), []byte(`{"x":2}`), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".jq"), []byte("def twice: . * 2;"), 0o600))
+
+	tests := []struct {
+		name string
+		expr string
+		want string
+	}{
+		{"import", `import "math" as m; m::inc`, "2"},
+		{"include", `include "math"; inc`, "2"},
+		{"data", `import "data" as $d; $d[0].x + .`, "3"},
+		{"init", "twice + 1", "3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &strings.Builder{}
+			w, err := gfmt.NewJQ(gfmt.NewJSON(b), tt.expr,
+				gfmt.WithModulePaths(filepath.Join(dir, ".jq"), filepath.Join(dir, "lib")))
+			require.NoError(t, err)
+			_, err = w.Write(1)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, b.String())
+		})
+	}
+
+	_, err := gfmt.NewJQ(gfmt.NewJSON(io.Discard), `import "math" as m; m::inc`)
+	require.Error(t, err)
+	_, err = gfmt.NewJQ(gfmt.NewJSON(io.Discard), `import "missing" as m; .`, gfmt.WithModulePaths(dir))
+	require.ErrorContains(t, err, "missing")
+}
@@ -0,0 +1,497 @@
+// Copyright 2025 The gutenfmt authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gfmt
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/abc-inc/gutenfmt/input"
+	"github.com/abc-inc/gutenfmt/internal/render"
+	"github.com/abc-inc/gutenfmt/internal/table"
+	"github.com/abc-inc/gutenfmt/ordered"
+	"github.com/itchyny/gojq"
+)
+
+// Function is a custom jq function, which can be registered on a JQ Writer.
+//
+// A Name starting with "@" defines a format, which is applied to the input
+// e.g., "@table", or to every interpolation of a string e.g., @table "\(.)".
+// Builtin functions and formats of jq take precedence over custom functions.
+type Function struct {
+	// Name is the name of the function e.g., "tokv" or "@table".
+	Name string
+	// MinArity and MaxArity limit the number of arguments.
+	MinArity, MaxArity int
+	// Call is invoked with the input and the evaluated arguments.
+	// It returns the result or an error.
+	Call func(v any, args []any) any
+}
+
+// Builtins holds the gutenfmt-specific jq functions:
+//
+//   - fromkv, tokv: lines of name and value pairs (see input.DecodeKV)
+//   - fromcsv, tocsv: comma-separated values with header (RFC 4180)
+//   - fromyaml, toyaml: YAML documents
+//   - flatten_paths, flatten_paths($sep): nested values as object with keys like "a.b.0"
+//   - unflatten_paths, unflatten_paths($sep): the reverse of flatten_paths
+//   - parse_duration: a Go duration like "1h30m" in seconds
+//   - parse_bytes: a byte size like "1.5 KiB" or "10MB" in bytes
+//   - parse_semver: a semantic version as object of major, minor, patch, prerelease and build
+//   - @table: the input rendered as table by Tab
+var Builtins = []Function{
+	{"fromkv", 0, 0, fromKV},
+	{"tokv", 0, 0, toKV},
+	{"fromcsv", 0, 0, fromCSV},
+	{"tocsv", 0, 0, toCSV},
+	{"fromyaml", 0, 0, fromYAML},
+	{"toyaml", 0, 0, toYAML},
+	{"flatten_paths", 0, 1, flattenPaths},
+	{"unflatten_paths", 0, 1, unflattenPaths},
+	{"parse_duration", 0, 0, parseDuration},
+	{"parse_bytes", 0, 0, parseBytes},
+	{"parse_semver", 0, 0, parseSemver},
+	{"@table", 0, 0, toTable},
+}
+
+// WithFunctions registers custom functions on a JQ Writer.
+func WithFunctions(fs ...Function) Opt[JQ] {
+	return func(w *JQ) {
+		w.Functions = append(w.Functions, fs...)
+	}
+}
+
+// WithBuiltins registers the gutenfmt-specific jq functions (see Builtins) on a JQ Writer.
+func WithBuiltins() Opt[JQ] {
+	return WithFunctions(Builtins...)
+}
+
+// formats returns the names of all registered formats.
+func (w JQ) formats() map[string]bool {
+	fs := map[string]bool{}
+	for _, f := range w.Functions {
+		if strings.HasPrefix(f.Name, "@") {
+			fs[f.Name] = true
+		}
+	}
+	return fs
+}
+
+// rewriteQuery rewrites the query, because gojq neither allows to define
+// custom formats nor keeps the key order of objects.
+// Registered formats are replaced by calls of the respective functions and the
+// key order of object constructions is recorded (see rewriteObject).
+func rewriteQuery(q *gojq.Query, fs map[string]bool) {
+	if q != nil {
+		rewrite(reflect.ValueOf(q), fs)
+	}
+}
+
+// rewrite walks the syntax tree of a query and rewrites the registered formats and objects.
+func rewrite(v reflect.Value, fs map[string]bool) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return
+		}
+		rewrite(v.Elem(), fs)
+		if t, ok := v.Interface().(*gojq.Term); ok && t.Type == gojq.TermTypeFormat && fs[t.Format] {
+			rewriteFormat(t)
+		} else if ok && t.Type == gojq.TermTypeObject {
+			rewriteObject(t)
+		}
+	case reflect.Slice:
+		for idx := 0; idx < v.Len(); idx++ {
+			rewrite(v.Index(idx), fs)
+		}
+	case reflect.Struct:
+		for idx := 0; idx < v.NumField(); idx++ {
+			if v.Type().Field(idx).IsExported() {
+				rewrite(v.Field(idx), fs)
+			}
+		}
+	}
+}
+
+// rewriteFormat replaces a format by a function call.
+// If the format is applied to a string, every interpolation is piped to the function.
+func rewriteFormat(t *gojq.Term) {
+	f := &gojq.Query{Term: &gojq.Term{Type: gojq.TermTypeFunc, Func: &gojq.Func{Name: t.Format}}}
+	if t.Str == nil {
+		t.Type, t.Func, t.Format = gojq.TermTypeFunc, f.Term.Func, ""
+		return
+	}
+	for idx, q := range t.Str.Queries {
+		if q.Term == nil || q.Term.Str == nil {
+			t.Str.Queries[idx] = &gojq.Query{Term: &gojq.Term{Type: gojq.TermTypeQuery,
+				Query: &gojq.Query{Left: q, Op: gojq.OpPipe, Right: f}}}
+		}
+	}
+	t.Type, t.Format = gojq.TermTypeString, ""
+}
+
+// jqModuleLoader rewrites the modules loaded by a gojq module loader.
+type jqModuleLoader struct {
+	loader  gojq.ModuleLoader
+	formats map[string]bool
+}
+
+// LoadInitModules loads the modules, whose definitions are available to the query.
+func (l jqModuleLoader) LoadInitModules() ([]*gojq.Query, error) {
+	ml, ok := l.loader.(interface{ LoadInitModules() ([]*gojq.Query, error) })
+	if !ok {
+		return nil, nil
+	}
+	qs, err := ml.LoadInitModules()
+	for _, q := range qs {
+		rewriteQuery(q, l.formats)
+	}
+	return qs, err
+}
+
+// LoadModuleWithMeta loads an imported or included module.
+func (l jqModuleLoader) LoadModuleWithMeta(name string, meta map[string]any) (*gojq.Query, error) {
+	ml, ok := l.loader.(interface {
+		LoadModuleWithMeta(string, map[string]any) (*gojq.Query, error)
+	})
+	if !ok {
+		return nil, fmt.Errorf("module not found: %q", name)
+	}
+	q, err := ml.LoadModuleWithMeta(name, meta)
+	rewriteQuery(q, l.formats)
+	return q, err
+}
+
+// LoadJSONWithMeta loads an imported JSON file.
+func (l jqModuleLoader) LoadJSONWithMeta(name string, meta map[string]any) (any, error) {
+	ml, ok := l.loader.(interface {
+		LoadJSONWithMeta(string, map[string]any) (any, error)
+	})
+	if !ok {
+		return nil, fmt.Errorf("module not found: %q", name)
+	}
+	return ml.LoadJSONWithMeta(name, meta)
+}
+
+// str returns the input of a function as string, or an error if it is not a string.
+func str(name string, v any) (string, error) {
+	if s, ok := v.(string); ok {
+		return s, nil
+	}
+	return "", fmt.Errorf("%s cannot be applied to: %s", name, describe(v))
+}
+
+// describe returns the JSON representation of a value for error messages.
+func describe(v any) string {
+	if b, err := json.Marshal(v); err == nil {
+		return string(b)
+	}
+	return fmt.Sprint(v)
+}
+
+// decodeWith decodes a string input and converts the result, so that it can be processed by jq.
+func decodeWith(name string, v any, d func(r io.Reader) (any, error)) any {
+	s, err := str(name, v)
+	if err != nil {
+		return err
+	}
+	r, err := d(strings.NewReader(s))
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	if st, ok := r.(input.Stream); ok {
+		r = []any(st)
+	}
+	if r, err = normalize(reflect.ValueOf(r), nil); err != nil {
+		return err
+	}
+	return r
+}
+
+// encodeWith renders the input by a Writer and returns the output without trailing line break.
+func encodeWith(v any, newWriter func(w io.Writer) Writer) any {
+	b := &strings.Builder{}
+	if _, err := newWriter(b).Write(v); err != nil {
+		return err
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+func fromKV(v any, _ []any) any {
+	return decodeWith("fromkv", v, input.DecodeKV)
+}
+
+func toKV(v any, _ []any) any {
+	if _, ok := v.(map[string]any); !ok {
+		return fmt.Errorf("tokv cannot be applied to: %s", describe(v))
+	}
+	return encodeWith(v, func(w io.Writer) Writer {
+		tw := NewText(w, WithSortKeys[Text]())
+		tw.Sep = "="
+		return tw
+	})
+}
+
+func fromCSV(v any, _ []any) any {
+	return decodeWith("fromcsv", v, input.CSV{Comma: ','}.Decode)
+}
+
+// toCSV renders an array of objects as CSV with header, or an array of arrays as CSV without header.
+func toCSV(v any, _ []any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		v = []any{t}
+	case []any:
+		if len(t) > 0 {
+			if _, ok := t[0].([]any); ok {
+				return toCSVRows(t)
+			}
+		}
+	default:
+		return fmt.Errorf("tocsv cannot be applied to: %s", describe(v))
+	}
+	return encodeWith(v, func(w io.Writer) Writer {
+		return NewCSV(w, WithSortKeys[CSV]())
+	})
+}
+
+// toCSVRows renders an array of arrays as CSV.
+func toCSVRows(rs []any) any {
+	b := &strings.Builder{}
+	cw := csv.NewWriter(b)
+	for _, r := range rs {
+		cs, ok := r.([]any)
+		if !ok {
+			return fmt.Errorf("tocsv cannot be applied to a row: %s", describe(r))
+		}
+		rec := make([]string, len(cs))
+		for idx, c := range cs {
+			if c != nil {
+				rec[idx] = render.ToString(c)
+			}
+		}
+		if err := cw.Write(rec); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return err
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+func fromYAML(v any, _ []any) any {
+	return decodeWith("fromyaml", v, input.DecodeYAML)
+}
+
+func toYAML(v any, _ []any) any {
+	return encodeWith(v, func(w io.Writer) Writer {
+		return NewYAML(w)
+	})
+}
+
+// toTable renders the input by Tab, without the trailing whitespace of the cells in the last column.
+func toTable(v any, _ []any) any {
+	s := encodeWith(v, func(w io.Writer) Writer {
+		return NewTab(w, WithSortKeys[Tab]())
+	})
+	if t, ok := s.(string); ok {
+		ls := strings.Split(t, "\n")
+		for idx, l := range ls {
+			ls[idx] = strings.TrimRight(l, " ")
+		}
+		return strings.Join(ls, "\n")
+	}
+	return s
+}
+
+// separator returns the optional separator argument of flatten_paths and unflatten_paths.
+func separator(name string, args []any) (string, error) {
+	if len(args) == 0 {
+		return ".", nil
+	}
+	if s, ok := args[0].(string); ok && s != "" {
+		return s, nil
+	}
+	return "", fmt.Errorf("%s: separator must be a non-empty string: %s", name, describe(args[0]))
+}
+
+func flattenPaths(v any, args []any) any {
+	sep, err := separator("flatten_paths", args)
+	if err != nil {
+		return err
+	}
+	r, ok := table.Record(v, table.Options{Flatten: true, Separator: sep, SortKeys: true})
+	if !ok {
+		return fmt.Errorf("flatten_paths cannot be applied to: %s", describe(v))
+	}
+	if r, err := normalize(reflect.ValueOf(r), nil); err != nil {
+		return err
+	} else if m, ok := r.(map[string]any); ok {
+		return m
+	}
+	return map[string]any{}
+}
+
+func unflattenPaths(v any, args []any) any {
+	sep, err := separator("unflatten_paths", args)
+	if err != nil {
+		return err
+	}
+	m, ok := v.(map[string]any)
+	if !ok {
+		return fmt.Errorf("unflatten_paths cannot be applied to: %s", describe(v))
+	}
+
+	// Only objects created for a path are extended, so that the values of m are never modified.
+	r, made := map[string]any{}, map[string]bool{}
+	for _, k := range ordered.SortedKeys(m) {
+		ps := strings.Split(k, sep)
+		n := r
+		for idx, p := range ps[:len(ps)-1] {
+			if path := strings.Join(ps[:idx+1], sep); !made[path] {
+				if _, ok := n[p]; ok {
+					return fmt.Errorf("unflatten_paths: conflicting key: %s", k)
+				}
+				n[p], made[path] = map[string]any{}, true
+			}
+			n = n[p].(map[string]any)
+		}
+		if _, ok := n[ps[len(ps)-1]]; ok {
+			return fmt.Errorf("unflatten_paths: conflicting key: %s", k)
+		}
+		n[ps[len(ps)-1]] = m[k]
+	}
+	return toArrays(r)
+}
+
+// toArrays converts the maps, whose keys are the indices 0 to n-1, to arrays, recursively.
+func toArrays(v any) any {
+	m, ok := v.(map[string]any)
+	if !ok {
+		return v
+	}
+	for k, e := range m {
+		m[k] = toArrays(e)
+	}
+	s := make([]any, len(m))
+	for k, e := range m {
+		idx, err := strconv.Atoi(k)
+		if err != nil || idx < 0 || idx >= len(m) || strconv.Itoa(idx) != k {
+			return m
+		}
+		s[idx] = e
+	}
+	if len(s) == 0 {
+		return m
+	}
+	return s
+}
+
+func parseDuration(v any, _ []any) any {
+	s, err := str("parse_duration", v)
+	if err != nil {
+		return err
+	}
+	d, err := time.ParseDuration(strings.TrimSpace(s))
+	if err != nil {
+		return fmt.Errorf("parse_duration: %w", err)
+	}
+	return d.Seconds()
+}
+
+// parseBytes parses a byte size like "512", "1.5 KiB", "10MB" or "2G".
+// Units with "i" e.g., "KiB", and single letters e.g., "K", are binary (1024),
+// whereas units with "B" e.g., "kB", are decimal (1000).
+func parseBytes(v any, _ []any) any {
+	s, err := str("parse_bytes", v)
+	if err != nil {
+		return err
+	}
+	s = strings.TrimSpace(s)
+	num := strings.TrimRightFunc(s, unicode.IsLetter)
+	unit := strings.ToUpper(strings.TrimSpace(s[len(num):]))
+	f, err := strconv.ParseFloat(strings.TrimSpace(num), 64)
+	if err != nil || f < 0 {
+		return fmt.Errorf("parse_bytes: invalid byte size: %q", s)
+	}
+
+	base, exp := 1024.0, 0
+	if unit != "" && unit != "B" {
+		exp = strings.IndexByte(byteUnits, unit[0]) + 1
+		switch unit[1:] {
+		case "", "IB":
+		case "B":
+			base = 1000
+		default:
+			exp = 0
+		}
+		if exp == 0 {
+			return fmt.Errorf("parse_bytes: invalid unit: %q", s)
+		}
+	}
+
+	f = math.Round(f * math.Pow(base, float64(exp)))
+	if f < math.MaxInt {
+		return int(f)
+	}
+	b, _ := big.NewFloat(f).Int(nil)
+	return b
+}
+
+// semver matches a semantic version (see https://semver.org) with optional leading "v".
+var semver = regexp.MustCompile(`^v?(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)` +
+	`(?:-((?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?` +
+	`(?:\+([0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?$`)
+
+// parseSemver parses a semantic version.
+// The prerelease and build identifiers are null, if absent.
+func parseSemver(v any, _ []any) any {
+	s, err := str("parse_semver", v)
+	if err != nil {
+		return err
+	}
+	ms := semver.FindStringSubmatch(strings.TrimSpace(s))
+	if ms == nil {
+		return fmt.Errorf("parse_semver: invalid semantic version: %q", s)
+	}
+
+	r := map[string]any{"prerelease": nil, "build": nil}
+	for idx, k := range []string{"major", "minor", "patch"} {
+		n, err := strconv.Atoi(ms[idx+1])
+		if err != nil {
+			return fmt.Errorf("parse_semver: %w", err)
+		}
+		r[k] = n
+	}
+	if ms[4] != "" {
+		r["prerelease"] = ms[4]
+	}
+	if ms[5] != "" {
+		r["build"] = ms[5]
+	}
+	return r
+}
@@ -0,0 +1,133 @@
+// Copyright 2025 The gutenfmt authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gfmt_test
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/abc-inc/gutenfmt/gfmt"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuiltins(t *testing.T) {
+	tests := []struct {
+		name string
+		in   any
+		expr string
+		want string
+	}{
+		{"fromkv", "a=1\nb: x\n\nc\td=e", "fromkv", `{"a":"1","b":" x","c":"d=e"}`},
+		{"tokv", map[string]any{"b": 2, "a": "x y"}, "tokv", `"a=x y\nb=2"`},
+		{"fromcsv", "a,b\n1,\"x,\"\"y\"\"\"\n", "fromcsv", `[{"a":"1","b":"x,\"y\""}]`},
+		{"tocsv_objects", []any{map[string]any{"a": 1, "b": "x,y"}, map[string]any{"a": 2, "b": nil}}, "tocsv", `"a,b\n1,\"x,y\"\n2,"`},
+		{"tocsv_object", map[string]any{"a": `"q"`}, "tocsv", `"a\n\"\"\"q\"\"\""`},
+		{"tocsv_rows", []any{[]any{1, "a b"}, []any{nil, "c\nd"}}, "tocsv", `"1,a b\n,\"c\nd\""`},
+		{"fromyaml", "a: 1\nb: [x, true]\n", "fromyaml", `{"a":1,"b":["x",true]}`},
+		{"fromyaml_stream", "a: 1\n---\na: 2\n", "fromyaml | map(.a)", `[1,2]`},
+		{"toyaml", map[string]any{"a": []any{1, "x"}}, "toyaml", `"a:\n  - 1\n  - x"`},
+		{"roundtrip", map[string]any{"a": 1, "b": "x"}, "tokv | fromkv", `{"a":"1","b":"x"}`},
+		{"flatten_paths", map[string]any{"a": map[string]any{"b": 1, "c": []any{2, 3}}, "d": nil}, "flatten_paths", `{"a.b":1,"a.c.0":2,"a.c.1":3,"d":null}`},
+		{"flatten_paths_sep", map[string]any{"a": map[string]any{"b": 1}}, `flatten_paths("/")`, `{"a/b":1}`},
+		{"unflatten_paths", map[string]any{"a.b": 1, "a.c.0": 2, "a.c.1": 3, "d": map[string]any{}}, "unflatten_paths", `{"a":{"b":1,"c":[2,3]},"d":{}}`},
+		{"unflatten_paths_sep", map[string]any{"a/b": 1, "a/2": 2}, `unflatten_paths("/")`, `{"a":{"2":2,"b":1}}`},
+		{"flatten_roundtrip", map[string]any{"a": []any{map[string]any{"b": true}}}, "flatten_paths | unflatten_paths", `{"a":[{"b":true}]}`},
+		{"parse_duration", "1h30m0.5s", "parse_duration", "5400.5"},
+		{"parse_bytes", []any{"512", "2 B", "1.5 KiB", "10MB", "2G", "1 kb", "3TiB"}, "map(parse_bytes)", `[512,2,1536,10000000,2147483648,1000,3298534883328]`},
+		{"parse_semver", "v1.20.3-rc.1+build.5", "parse_semver", `{"major":1,"minor":20,"patch":3,"prerelease":"rc.1","build":"build.5"}`},
+		{"parse_semver_release", "0.1.0", "parse_semver", `{"major":0,"minor":1,"patch":0,"prerelease":null,"build":null}`},
+		{"table", []any{map[string]any{"b": 2, "a": 1}}, "@table", `"a   b\n1   2"`},
+		{"table_string", map[string]any{"x": map[string]any{"a": 1}}, `@table "T:\n\(.x)"`, `"T:\na   1"`},
+		{"table_nested", []any{[]any{map[string]any{"a": 1}}}, `map(@table)`, `["a\n1"]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &strings.Builder{}
+			w, err := gfmt.NewJQ(gfmt.NewJSON(b), tt.expr, gfmt.WithBuiltins())
+			require.NoError(t, err)
+			_, err = w.Write(tt.in)
+			require.NoError(t, err)
+			require.JSONEq(t, tt.want, b.String())
+		})
+	}
+}
+
+func TestBuiltins_Error(t *testing.T) {
+	tests := []struct {
+		name string
+		in   any
+		expr string
+		want string
+	}{
+		{"fromkv", 1, "fromkv", "fromkv cannot be applied to: 1"},
+		{"tokv", []any{1}, "tokv", "tokv cannot be applied to: [1]"},
+		{"fromcsv", "a\n\"b", "fromcsv", "fromcsv:"},
+		{"tocsv", []any{[]any{1}, 2}, "tocsv", "tocsv cannot be applied to a row: 2"},
+		{"fromyaml", "a: [", "fromyaml", "fromyaml:"},
+		{"flatten_paths", []any{1}, "flatten_paths", "flatten_paths cannot be applied to: [1]"},
+		{"flatten_paths_sep", map[string]any{}, `flatten_paths("")`, "separator must be a non-empty string"},
+		{"unflatten_paths", map[string]any{"a": 1, "a.b": 2}, "unflatten_paths", "conflicting key: a.b"},
+		{"unflatten_paths_object", map[string]any{"a": map[string]any{}, "a.b": 2}, "unflatten_paths", "conflicting key: a.b"},
+		{"parse_duration", "1 day", "parse_duration", "parse_duration:"},
+		{"parse_bytes", "1 XB", "parse_bytes", "invalid unit"},
+		{"parse_bytes_number", "-1", "parse_bytes", "invalid byte size"},
+		{"parse_semver", "1.02.3", "parse_semver", "invalid semantic version"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, err := gfmt.NewJQ(gfmt.NewJSON(io.Discard), tt.expr, gfmt.WithBuiltins())
+			require.NoError(t, err)
+			_, err = w.Write(tt.in)
+			require.ErrorContains(t, err, tt.want)
+		})
+	}
+}
+
+func TestWithFunctions(t *testing.T) {
+	upper := gfmt.Function{Name: "@upper", MaxArity: 0, Call: func(v any, _ []any) any {
+		s, _ := v.(string)
+		return strings.ToUpper(s)
+	}}
+	times := gfmt.Function{Name: "times", MinArity: 1, MaxArity: 1, Call: func(v any, args []any) any {
+		return strings.Repeat(v.(string), args[0].(int))
+	}}
+
+	b := &strings.Builder{}
+	w, err := gfmt.NewJQ(gfmt.NewJSON(b), `[times(2), @upper, @upper "x\(.)y", @base64]`, gfmt.WithFunctions(upper, times))
+	require.NoError(t, err)
+	_, err = w.Write("ab")
+	require.NoError(t, err)
+	require.Equal(t, `["abab","AB","xABy","YWI="]`, b.String())
+
+	_, err = gfmt.NewJQ(gfmt.NewJSON(b), "@upper | parse_bytes", gfmt.WithFunctions(upper))
+	require.ErrorContains(t, err, "function not defined: parse_bytes/0")
+}
+
+func TestWithFunctions_Modules(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "t.jq"), []byte(`def t: @table "<\(.)>";`), 0o600))
+
+	b := &strings.Builder{}
+	w, err := gfmt.NewJQ(gfmt.NewJSON(b), `include "t"; t`, gfmt.WithBuiltins(), gfmt.WithModulePaths(dir))
+	require.NoError(t, err)
+	_, err = w.Write(map[string]any{"a": 1})
+	require.NoError(t, err)
+	require.Equal(t, `"<a   1>"`, b.String())
+}
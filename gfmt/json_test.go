@@ -63,3 +63,10 @@ func TestJSON_WriteStruct(t *testing.T) {
 	require.Equal(t, 48, n)
 	require.Contains(t, b.String(), "\"username\":\"John Doe\",\"email\":\"john.doe@local\"")
 }
+
+func TestJSON_WriteOrderedMap(t *testing.T) {
+	b := &strings.Builder{}
+	_, err := gfmt.NewJSON(b, gfmt.WithPretty[gfmt.JSON]()).Write(newOrderedMap("z", 1, "a", []any{newOrderedMap("y", 2, "b", 3)}))
+	require.NoError(t, err)
+	require.Equal(t, "{\n  \"z\": 1,\n  \"a\": [\n    {\n      \"y\": 2,\n      \"b\": 3\n    }\n  ]\n}", b.String())
+}
@@ -0,0 +1,185 @@
+// Copyright 2026 The gutenfmt authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gfmt
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/abc-inc/gutenfmt/formatter"
+	"github.com/abc-inc/gutenfmt/internal/table"
+)
+
+// Align is the alignment of a table column.
+type Align int
+
+const (
+	// AlignDefault leaves the alignment to the renderer.
+	AlignDefault Align = iota
+	// AlignLeft aligns the column to the left.
+	AlignLeft
+	// AlignCenter centers the column.
+	AlignCenter
+	// AlignRight aligns the column to the right.
+	AlignRight
+	// AlignNumbers aligns the column to the right, if all values are numbers,
+	// or to the left otherwise.
+	// Markdown tables leave the alignment to the renderer.
+	AlignNumbers
+)
+
+// delimiter returns the cell of the delimiter row, which defines the alignment.
+func (a Align) delimiter() string {
+	switch a {
+	case AlignLeft:
+		return ":---"
+	case AlignCenter:
+		return ":---:"
+	case AlignRight:
+		return "---:"
+	default:
+		return "---"
+	}
+}
+
+// Markdown is a generic Writer that formats arbitrary values as GitHub Flavored Markdown.
+//
+// Slices of maps and structs are written as table with one row per element.
+// A single map or struct is written as table with one row per entry, each
+// consisting of name and value. Any other slice is written as bullet list.
+type Markdown struct {
+	writer    io.Writer
+	Formatter *formatter.CompFormatter
+	// Align holds the alignment per column name.
+	Align map[string]Align
+	// SortKeys sorts the keys of maps, which do not define an order.
+	SortKeys bool
+}
+
+// NewMarkdown creates a new Markdown Writer.
+func NewMarkdown(w io.Writer, opts ...Opt[Markdown]) *Markdown {
+	gw := &Markdown{writer: w, Formatter: formatter.NewComp(), Align: map[string]Align{}}
+	for _, opt := range opts {
+		opt(gw)
+	}
+	return gw
+}
+
+// Write writes the Markdown representation of the given value to the underlying Writer.
+func (w Markdown) Write(i any) (int, error) {
+	if i == nil {
+		return 0, nil
+	}
+
+	if s, err := w.Formatter.Format(i); err == nil {
+		return io.WriteString(w.writer, s)
+	} else if !errors.Is(err, formatter.ErrUnsupported) {
+		return 0, err
+	}
+
+	t, ok := table.From(i, table.Options{SortKeys: w.SortKeys})
+	if !ok {
+		if typ := reflect.TypeOf(i); typ.Kind() == reflect.Ptr {
+			return w.Write(reflect.Indirect(reflect.ValueOf(i)).Interface())
+		}
+		s, err := cellString(i)
+		if err != nil {
+			return 0, err
+		}
+		return io.WriteString(w.writer, s)
+	}
+
+	b := &strings.Builder{}
+	var err error
+	if t.Header == nil && (len(t.Rows) == 0 || len(t.Rows[0]) == 1) {
+		err = w.writeList(b, t)
+	} else {
+		err = w.writeTable(b, t)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return io.WriteString(w.writer, strings.TrimSuffix(b.String(), "\n"))
+}
+
+// writeList writes a single-column Table as bullet list.
+func (w Markdown) writeList(b *strings.Builder, t *table.Table) error {
+	for _, row := range t.Rows {
+		s, err := cellString(row[0])
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(b, "- %s\n", escapeMarkdown(s))
+	}
+	return nil
+}
+
+// writeTable writes a Table with header and delimiter row.
+// Name and value pairs get the header "Name" and "Value".
+func (w Markdown) writeTable(b *strings.Builder, t *table.Table) error {
+	hdr := t.Header
+	if hdr == nil {
+		hdr = []string{"Name", "Value"}
+	}
+
+	cs := make([]string, len(hdr))
+	for idx, h := range hdr {
+		cs[idx] = escapeMarkdown(h)
+	}
+	writeMarkdownRow(b, cs)
+	for idx, h := range hdr {
+		cs[idx] = w.Align[h].delimiter()
+	}
+	writeMarkdownRow(b, cs)
+
+	for _, row := range t.Rows {
+		for idx, c := range row {
+			s, err := cellString(c)
+			if err != nil {
+				return err
+			}
+			cs[idx] = escapeMarkdown(s)
+		}
+		writeMarkdownRow(b, cs)
+	}
+	return nil
+}
+
+// writeMarkdownRow writes the cells enclosed in pipes.
+func writeMarkdownRow(b *strings.Builder, cs []string) {
+	b.WriteString("|")
+	for _, c := range cs {
+		b.WriteString(" " + c + " |")
+	}
+	b.WriteString("\n")
+}
+
+// markdownEscaper escapes characters, which would break the structure of a table.
+var markdownEscaper = strings.NewReplacer("\\", "\\\\", "|", "\\|", "\r\n", "<br>", "\n", "<br>", "\r", "<br>")
+
+// escapeMarkdown escapes backslashes and pipes, and replaces line breaks with <br>.
+func escapeMarkdown(s string) string {
+	return markdownEscaper.Replace(s)
+}
+
+// WithAlign sets the alignment of a column in a Markdown table.
+func WithAlign(col string, a Align) Opt[Markdown] {
+	return func(w *Markdown) {
+		w.Align[col] = a
+	}
+}
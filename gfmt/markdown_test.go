@@ -0,0 +1,69 @@
+// Copyright 2026 The gutenfmt authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gfmt_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/abc-inc/gutenfmt/gfmt"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarkdown_Write(t *testing.T) {
+	tests := []struct {
+		name string
+		arg  any
+		want string
+	}{
+		{"nil", nil, ""},
+		{"int", -42, "-42"},
+		{"int_slice", []int{1, 2}, "- 1\n- 2"},
+		{"empty_slice", []string{}, ""},
+		{"struct", NewUser("John", "Doe"), heredoc.Doc(`
+			| Name | Value |
+			| --- | --- |
+			| username | John Doe |
+			| email | john.doe@local |`)},
+		{"struct_slice", []User{*NewUser("John", "Doe")}, heredoc.Doc(`
+			| username | email |
+			| --- | --- |
+			| John Doe | john.doe@local |`)},
+		{"map_slice", []any{newOrderedMap("a|b", "x|y", "c", "1\n2"), newOrderedMap("c", []any{1})}, heredoc.Doc(`
+			| a\|b | c |
+			| --- | --- |
+			| x\|y | 1<br>2 |
+			|  | [1] |`)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &strings.Builder{}
+			_, err := gfmt.NewMarkdown(b).Write(tt.arg)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, b.String())
+		})
+	}
+}
+
+func TestMarkdown_WriteAlign(t *testing.T) {
+	b := &strings.Builder{}
+	w := gfmt.NewMarkdown(b, gfmt.WithAlign("a", gfmt.AlignLeft), gfmt.WithAlign("b", gfmt.AlignCenter),
+		gfmt.WithAlign("c", gfmt.AlignRight), gfmt.WithSortKeys[gfmt.Markdown]())
+	_, err := w.Write([]map[string]any{{"d": 4, "c": 3, "b": 2, "a": 1}})
+	require.NoError(t, err)
+	require.Equal(t, "| a | b | c | d |\n| :--- | :---: | ---: | --- |\n| 1 | 2 | 3 | 4 |", b.String())
+}
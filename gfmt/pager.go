@@ -0,0 +1,190 @@
+// Copyright 2026 The gutenfmt authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gfmt
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/abc-inc/gutenfmt/internal/width"
+	"github.com/mattn/go-isatty"
+)
+
+// DefaultPager is the pager command, if neither GUTENFMT_PAGER nor PAGER is set.
+const DefaultPager = "less -FRX"
+
+// lessVersion extracts the version number from the output of "less --version".
+var lessVersion = regexp.MustCompile(`^less (\d+)`)
+
+// Pager buffers the output and writes it through a pager, if it is taller than the terminal.
+// As soon as the output is taller, the pager is started and the output is written through.
+// If paging is disabled, the output is written directly.
+//
+// Like git, it runs the pager only if the output is a terminal. The pager is
+// given by the environment variable GUTENFMT_PAGER or PAGER, and defaults to
+// "less -FRX". An empty value or "cat" disables paging.
+type Pager struct {
+	out io.Writer
+	buf bytes.Buffer
+	cmd *exec.Cmd
+	in  io.WriteCloser
+	// Command is the pager command line, whose arguments are separated by spaces.
+	Command string
+	// Width is the number of columns of the terminal, 0 means unknown.
+	// Longer lines are counted as multiple lines.
+	Width int
+	// Height is the number of lines of the terminal, 0 disables paging.
+	Height int
+	// Header is the number of leading lines, which stay visible while scrolling.
+	// It is only supported by less 600 or newer.
+	Header int
+}
+
+var _ io.WriteCloser = (*Pager)(nil)
+
+// NewPager creates a new Pager, which writes to f.
+// Paging is disabled, if f is not a terminal.
+func NewPager(f *os.File) *Pager {
+	p := &Pager{out: f, Command: PagerCommand()}
+	if isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd()) {
+		p.Width, p.Height = TerminalWidth(f.Fd()), TerminalHeight(f.Fd())
+	}
+	return p
+}
+
+// PagerCommand returns the pager command line from the environment variable
+// GUTENFMT_PAGER or PAGER, or DefaultPager if neither is set.
+func PagerCommand() string {
+	for _, k := range []string{"GUTENFMT_PAGER", "PAGER"} {
+		if cmd, ok := os.LookupEnv(k); ok {
+			return cmd
+		}
+	}
+	return DefaultPager
+}
+
+// Write appends b to the buffer, until the output is taller than the terminal.
+// Then, the pager is started and the buffered output as well as b are written to the pager.
+func (p *Pager) Write(b []byte) (int, error) {
+	switch {
+	case p.in != nil:
+		n, err := p.in.Write(b)
+		if errors.Is(err, syscall.EPIPE) {
+			// The pager was quit, so there is no need to write the rest.
+			return len(b), nil
+		}
+		return n, err
+	case !p.paging():
+		return p.out.Write(b)
+	}
+
+	n, _ := p.buf.Write(b)
+	if p.lines() <= p.Height {
+		return n, nil
+	}
+	if err := p.start(); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+// Close writes the buffered output and waits for the pager to exit.
+func (p *Pager) Close() error {
+	if p.in == nil {
+		_, err := p.out.Write(p.buf.Bytes())
+		p.buf.Reset()
+		return err
+	}
+
+	if err := p.in.Close(); err != nil {
+		return err
+	} else if err := p.cmd.Wait(); err != nil {
+		return fmt.Errorf("cannot run pager %q: %w", p.Command, err)
+	}
+	return nil
+}
+
+// paging returns true if the output is a terminal and the pager is enabled.
+func (p *Pager) paging() bool {
+	args := strings.Fields(p.Command)
+	return p.Height > 0 && len(args) > 0 && args[0] != "cat"
+}
+
+// start starts the pager and writes the buffered output to it.
+// If the pager cannot be found, paging is disabled and the output is written directly.
+func (p *Pager) start() error {
+	args := strings.Fields(p.Command)
+	if p.Header > 0 && isLess(args[0]) && supportsHeader(args[0]) {
+		args = append(args, "--header="+strconv.Itoa(p.Header))
+	}
+	cmd := exec.Command(args[0], args[1:]...) //nolint:gosec
+	cmd.Stdout, cmd.Stderr = p.out, os.Stderr
+	in, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+
+	defer p.buf.Reset()
+	if err = cmd.Start(); errors.Is(err, exec.ErrNotFound) {
+		p.Height = 0
+		_, err = p.out.Write(p.buf.Bytes())
+		return err
+	} else if err != nil {
+		return fmt.Errorf("cannot run pager %q: %w", p.Command, err)
+	}
+	p.cmd, p.in = cmd, in
+	_, err = p.Write(p.buf.Bytes())
+	return err
+}
+
+// lines returns the number of lines the buffered output occupies on the terminal.
+func (p *Pager) lines() int {
+	n := 0
+	for _, l := range strings.Split(strings.TrimSuffix(p.buf.String(), "\n"), "\n") {
+		n++
+		if w := width.String(l); p.Width > 0 && w > p.Width {
+			n += (w - 1) / p.Width
+		}
+	}
+	return n
+}
+
+// isLess returns true if the command is less.
+func isLess(cmd string) bool {
+	return strings.TrimSuffix(strings.ToLower(filepath.Base(cmd)), ".exe") == "less"
+}
+
+// supportsHeader returns true if the version of less supports the --header option.
+func supportsHeader(cmd string) bool {
+	out, err := exec.Command(cmd, "--version").Output() //nolint:gosec
+	if err != nil {
+		return false
+	}
+	m := lessVersion.FindSubmatch(out)
+	if m == nil {
+		return false
+	}
+	v, _ := strconv.Atoi(string(m[1]))
+	return v >= 600
+}
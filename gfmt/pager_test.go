@@ -0,0 +1,116 @@
+// Copyright 2026 The gutenfmt authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gfmt_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/abc-inc/gutenfmt/gfmt"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPagerCommand(t *testing.T) {
+	t.Setenv("GUTENFMT_PAGER", "more")
+	t.Setenv("PAGER", "most")
+	require.Equal(t, "more", gfmt.PagerCommand())
+
+	require.NoError(t, os.Unsetenv("GUTENFMT_PAGER"))
+	require.Equal(t, "most", gfmt.PagerCommand())
+
+	require.NoError(t, os.Unsetenv("PAGER"))
+	require.Equal(t, gfmt.DefaultPager, gfmt.PagerCommand())
+}
+
+func TestPager_Close(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("tr is not available on Windows")
+	}
+
+	tests := []struct {
+		name    string
+		command string
+		height  int
+		width   int
+		in      string
+		want    string
+	}{
+		{"not_a_terminal", "tr a-z A-Z", 0, 0, "a\nb\nc\n", "a\nb\nc\n"},
+		{"fits", "tr a-z A-Z", 3, 0, "a\nb\nc\n", "a\nb\nc\n"},
+		{"taller", "tr a-z A-Z", 2, 0, "a\nb\nc\n", "A\nB\nC\n"},
+		{"fits_wide", "tr a-z A-Z", 2, 0, "ab\nc", "ab\nc"},
+		{"wrapped", "tr a-z A-Z", 2, 1, "ab\nc", "AB\nC"},
+		{"cat", "cat", 1, 0, "a\nb\nc\n", "a\nb\nc\n"},
+		{"disabled", "", 1, 0, "a\nb\nc\n", "a\nb\nc\n"},
+		{"not_found", "gutenfmt-no-such-pager", 1, 0, "a\nb\nc\n", "a\nb\nc\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := os.Create(filepath.Join(t.TempDir(), "out"))
+			require.NoError(t, err)
+			defer func() { _ = f.Close() }()
+
+			p := gfmt.NewPager(f)
+			require.Zero(t, p.Height)
+			p.Command, p.Height, p.Width = tt.command, tt.height, tt.width
+			_, err = p.Write([]byte(tt.in))
+			require.NoError(t, err)
+			require.NoError(t, p.Close())
+
+			b, err := os.ReadFile(f.Name())
+			require.NoError(t, err)
+			require.Equal(t, tt.want, string(b))
+		})
+	}
+}
+
+func TestPager_Write(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("tr is not available on Windows")
+	}
+
+	f, err := os.Create(filepath.Join(t.TempDir(), "out"))
+	require.NoError(t, err)
+	defer func() { _ = f.Close() }()
+
+	// Without a terminal, the output is not buffered.
+	p := gfmt.NewPager(f)
+	p.Command = "tr a-z A-Z"
+	_, err = p.Write([]byte("a\n"))
+	require.NoError(t, err)
+	b, err := os.ReadFile(f.Name())
+	require.NoError(t, err)
+	require.Equal(t, "a\n", string(b))
+
+	// Once the output is taller than the terminal, it is written through the pager.
+	p.Height = 2
+	for _, s := range []string{"b\n", "c\n", "d\n", "e\n"} {
+		_, err = p.Write([]byte(s))
+		require.NoError(t, err)
+	}
+	require.NoError(t, p.Close())
+	b, err = os.ReadFile(f.Name())
+	require.NoError(t, err)
+	require.Equal(t, "a\nB\nC\nD\nE\n", string(b))
+}
+
+func TestTableStyle_HeaderLines(t *testing.T) {
+	require.Equal(t, 1, gfmt.StylePlain.HeaderLines())
+	require.Equal(t, 2, gfmt.StyleCompact.HeaderLines())
+	require.Equal(t, 3, gfmt.StyleLight.HeaderLines())
+}
@@ -0,0 +1,349 @@
+// Copyright 2026 The gutenfmt authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gfmt
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/abc-inc/gutenfmt/internal/render"
+	"github.com/abc-inc/gutenfmt/internal/table"
+	"github.com/abc-inc/gutenfmt/ordered"
+)
+
+// Unit determines how the numbers of a column are interpreted.
+type Unit int
+
+const (
+	// UnitNone keeps numbers as is.
+	UnitNone Unit = iota
+	// UnitBytes renders sizes in bytes using binary prefixes e.g., "1.5 MiB".
+	UnitBytes
+	// UnitDuration renders nanoseconds like time.Duration e.g., "1m30s".
+	UnitDuration
+	// UnitTime renders seconds since the Unix epoch and strings holding times as time.
+	UnitTime
+)
+
+// byteUnits holds the binary prefixes of byte sizes.
+const byteUnits = "KMGTPE"
+
+// now returns the current time, which relative times refer to.
+var now = time.Now
+
+// presents returns true if the values of any column are rendered or aligned depending on their values.
+func (w Tab) presents() bool {
+	for _, c := range w.Columns {
+		if c.Align == AlignNumbers || c.Thousands || c.Precision != nil || c.Unit != UnitNone ||
+			c.TimeLayout != "" || c.Relative || c.Location != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// column returns the Column of the given name or the default Column.
+// Settings, which are not defined for the named Column, are inherited from the default Column.
+func (w Tab) column(name string) Column {
+	if c, ok := w.Columns[name]; ok {
+		return c.inherit(w.Columns[""])
+	}
+	return w.Columns[""]
+}
+
+// inherit sets all settings of c, which are not defined, to the ones of d.
+func (c Column) inherit(d Column) Column {
+	if c.MaxWidth == 0 {
+		c.MaxWidth, c.Overflow = d.MaxWidth, d.Overflow
+	}
+	if c.Align == AlignDefault {
+		c.Align = d.Align
+	}
+	if c.Precision == nil {
+		c.Precision = d.Precision
+	}
+	if c.Unit == UnitNone {
+		c.Unit = d.Unit
+	}
+	if c.TimeLayout == "" {
+		c.TimeLayout = d.TimeLayout
+	}
+	if c.Location == nil {
+		c.Location = d.Location
+	}
+	c.Thousands = c.Thousands || d.Thousands
+	c.Relative = c.Relative || d.Relative
+	return c
+}
+
+// present converts records and slices of records to ordered.Maps, whose
+// values are rendered and highlighted as requested.
+// It returns the Columns with resolved alignment and without value presentation.
+func (w Tab) present(i any) (any, map[string]Column) {
+	o := table.Options{SortKeys: w.SortKeys}
+	if r, ok := table.Record(i, o); ok {
+		num := true
+		for _, k := range r.Keys() {
+			e, _ := r.Get(k)
+			num = num && w.column(k).numeric(e)
+		}
+		return w.presentRecord(r), w.layout([]string{"Name", "Value"}, map[string]bool{"Value": num})
+	}
+
+	v := reflect.ValueOf(i)
+	if v.Kind() == reflect.Ptr && !v.IsNil() {
+		return w.present(v.Elem().Interface())
+	} else if k := v.Kind(); (k != reflect.Slice && k != reflect.Array) || v.Len() == 0 || !table.IsRecord(v.Index(0).Interface()) {
+		return i, w.layout(nil, nil)
+	}
+
+	hdr, rs, _ := table.Records(i, o)
+	nums := make(map[string]bool, len(hdr))
+	for _, k := range hdr {
+		nums[k] = true
+	}
+	prs := make([]any, len(rs))
+	for idx, r := range rs {
+		for _, k := range r.Keys() {
+			e, _ := r.Get(k)
+			nums[k] = nums[k] && w.column(k).numeric(e)
+		}
+		prs[idx] = w.presentRecord(r)
+	}
+	return prs, w.layout(hdr, nums)
+}
+
+// presentRecord renders the values of a record and colors the ones, which match any Highlight.
+func (w Tab) presentRecord(r *ordered.Map) *ordered.Map {
+	colors := w.colors(r)
+	pr := ordered.NewMap(r.Len())
+	for _, k := range r.Keys() {
+		e, _ := r.Get(k)
+		e = w.column(k).value(e)
+		if seq, ok := colors[k]; ok && e != nil {
+			e = seq + render.ToString(e) + "\x1b[0m"
+		}
+		pr.Set(k, e)
+	}
+	return pr
+}
+
+// layout returns the Columns for the given column names, keeping only their
+// layout, and resolves AlignNumbers depending on whether a column holds numbers only.
+func (w Tab) layout(names []string, nums map[string]bool) map[string]Column {
+	cols := make(map[string]Column, len(w.Columns)+len(names))
+	for n := range w.Columns {
+		cols[n] = w.column(n).layout(nums[n])
+	}
+	if d, ok := w.Columns[""]; ok {
+		for _, n := range names {
+			if _, ok := w.Columns[n]; !ok {
+				cols[n] = d.layout(nums[n])
+			}
+		}
+	}
+	return cols
+}
+
+// layout returns a Column with the width and the alignment of c.
+func (c Column) layout(num bool) Column {
+	l := Column{MaxWidth: c.MaxWidth, Overflow: c.Overflow, Align: c.Align}
+	if l.Align == AlignNumbers {
+		l.Align = AlignLeft
+		if num {
+			l.Align = AlignRight
+		}
+	}
+	return l
+}
+
+// numeric returns true if i is nil or a number, which is not rendered as time.
+func (c Column) numeric(i any) bool {
+	if i == nil {
+		return true
+	} else if _, ok := i.(bool); ok || c.Unit == UnitTime {
+		return false
+	}
+	_, ok := toFloat(i)
+	return ok
+}
+
+// value renders i according to the Unit and the number and time settings.
+// Values, which cannot be interpreted accordingly, are returned as is.
+func (c Column) value(i any) any {
+	switch c.Unit {
+	case UnitBytes:
+		if f, ok := toFloat(i); ok {
+			return c.bytes(f)
+		}
+	case UnitDuration:
+		if d, ok := i.(time.Duration); ok {
+			return d.String()
+		} else if f, ok := toFloat(i); ok {
+			return time.Duration(f).String()
+		}
+	case UnitTime:
+		if t, ok := toTime(i); ok {
+			return c.time(t)
+		} else if f, ok := toFloat(i); ok {
+			sec, frac := math.Modf(f)
+			return c.time(time.Unix(int64(sec), int64(frac*1e9)).UTC())
+		}
+	}
+
+	if t, ok := toTime(i); ok && !isString(i) && (c.TimeLayout != "" || c.Relative || c.Location != nil) {
+		return c.time(t)
+	}
+	if _, ok := i.(fmt.Stringer); !ok && (c.Thousands || c.Precision != nil) {
+		if f, ok := toFloat(i); ok {
+			return c.number(i, f)
+		}
+	}
+	return i
+}
+
+// number renders a number with fixed precision and grouped digits, if requested.
+func (c Column) number(i any, f float64) string {
+	s := strings.TrimSpace(render.ToString(i))
+	if c.Precision != nil {
+		s = strconv.FormatFloat(f, 'f', *c.Precision, 64)
+	} else if k := reflect.ValueOf(i).Kind(); k == reflect.Float32 || k == reflect.Float64 {
+		// avoid the scientific notation of large numbers
+		s = strconv.FormatFloat(f, 'f', -1, reflect.TypeOf(i).Bits())
+	}
+	if c.Thousands {
+		s = group(s)
+	}
+	return s
+}
+
+// bytes renders a size in bytes using binary prefixes.
+// Sizes below 1 KiB are rendered as integers.
+func (c Column) bytes(f float64) string {
+	if math.Abs(f) < 1024 {
+		return strconv.FormatFloat(f, 'f', -1, 64) + " B"
+	}
+	exp := 0
+	for math.Abs(f) >= 1024 && exp < len(byteUnits) {
+		f /= 1024
+		exp++
+	}
+	p := 1
+	if c.Precision != nil {
+		p = *c.Precision
+	}
+	return strconv.FormatFloat(f, 'f', p, 64) + " " + byteUnits[exp-1:exp] + "iB"
+}
+
+// time renders t in the requested time zone, either relative to now or in the requested layout.
+func (c Column) time(t time.Time) string {
+	if c.Location != nil {
+		t = t.In(c.Location)
+	}
+	if c.Relative {
+		return relative(t, now())
+	}
+	if c.TimeLayout == "" {
+		return t.Format(time.RFC3339)
+	}
+	return t.Format(c.TimeLayout)
+}
+
+// relative renders the difference between t and now in the largest unit e.g., "3 hours ago" or "in 2 days".
+func relative(t, now time.Time) string {
+	d := now.Sub(t)
+	future := d < 0
+	if future {
+		d = -d
+	}
+	if d < time.Minute {
+		return "now"
+	}
+
+	units := []struct {
+		name string
+		d    time.Duration
+	}{
+		{"year", 365 * 24 * time.Hour},
+		{"month", 30 * 24 * time.Hour},
+		{"day", 24 * time.Hour},
+		{"hour", time.Hour},
+		{"minute", time.Minute},
+	}
+	for _, u := range units {
+		if d < u.d {
+			continue
+		}
+		n := int(d / u.d)
+		s := fmt.Sprintf("%d %s", n, u.name)
+		if n > 1 {
+			s += "s"
+		}
+		if future {
+			return "in " + s
+		}
+		return s + " ago"
+	}
+	return "now"
+}
+
+// group inserts a comma between each group of three digits of the integral part of a number.
+// Numbers in scientific notation are returned as is.
+func group(s string) string {
+	start := 0
+	if s != "" && (s[0] == '-' || s[0] == '+') {
+		start = 1
+	}
+	end := start
+	for end < len(s) && isDigit(s[end]) {
+		end++
+	}
+	if strings.ContainsAny(s[end:], "eE") {
+		return s
+	}
+
+	b := &strings.Builder{}
+	b.WriteString(s[:start])
+	digits := s[start:end]
+	for idx := range len(digits) {
+		if idx > 0 && (len(digits)-idx)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteByte(digits[idx])
+	}
+	b.WriteString(s[end:])
+	return b.String()
+}
+
+// isString returns true if i is a string.
+func isString(i any) bool {
+	_, ok := i.(string)
+	return ok
+}
+
+// WithColumn sets the layout and the presentation of the named column of a Tab Writer.
+// If name is empty, the Column applies to all columns without their own settings.
+func WithColumn(name string, c Column) Opt[Tab] {
+	return func(w *Tab) {
+		if w.Columns == nil {
+			w.Columns = map[string]Column{}
+		}
+		w.Columns[name] = c
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright 2026 The gutenfmt authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gfmt_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abc-inc/gutenfmt/gfmt"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTab_WriteAlign(t *testing.T) {
+	files := []any{
+		newOrderedMap("name", "a.txt", "size", 1234567, "ratio", 0.5),
+		newOrderedMap("name", "b.txt", "size", json.Number("42"), "ratio", "n/a"),
+	}
+
+	b := &strings.Builder{}
+	_, err := gfmt.NewTab(b, gfmt.WithColumn("", gfmt.Column{Align: gfmt.AlignNumbers})).Write(files)
+	require.NoError(t, err)
+	require.Equal(t, "name     size ratio \na.txt 1234567 0.5   \nb.txt      42 n/a   ", b.String())
+
+	b.Reset()
+	_, err = gfmt.NewTab(b, gfmt.WithColumn("size", gfmt.Column{Align: gfmt.AlignRight, Thousands: true}),
+		gfmt.WithTableStyle(gfmt.StyleASCII)).Write(files)
+	require.NoError(t, err)
+	require.Equal(t, `+-------+-----------+-------+
+| name  |      size | ratio |
++-------+-----------+-------+
+| a.txt | 1,234,567 | 0.5   |
+| b.txt |        42 | n/a   |
++-------+-----------+-------+`, b.String())
+
+	b.Reset()
+	_, err = gfmt.NewTab(b, gfmt.WithColumn("", gfmt.Column{Align: gfmt.AlignNumbers}),
+		gfmt.WithColumn("size", gfmt.Column{Thousands: true})).Write(files)
+	require.NoError(t, err)
+	require.Equal(t, "name       size ratio \na.txt 1,234,567 0.5   \nb.txt        42 n/a   ", b.String())
+}
+
+func TestTab_WriteValues(t *testing.T) {
+	two := 2
+	vienna, err := time.LoadLocation("Europe/Vienna")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		col  gfmt.Column
+		val  any
+		want string
+	}{
+		{"thousands", gfmt.Column{Thousands: true}, -9876543.21, "-9,876,543.21"},
+		{"thousands_string", gfmt.Column{Thousands: true}, "1000", "1,000"},
+		{"precision", gfmt.Column{Precision: &two}, 3.14159, "3.14"},
+		{"precision_thousands", gfmt.Column{Precision: &two, Thousands: true}, 1234, "1,234.00"},
+		{"bytes", gfmt.Column{Unit: gfmt.UnitBytes}, 1536, "1.5 KiB"},
+		{"bytes_small", gfmt.Column{Unit: gfmt.UnitBytes}, 512, "512 B"},
+		{"bytes_precision", gfmt.Column{Unit: gfmt.UnitBytes, Precision: &two}, int64(5 << 30), "5.00 GiB"},
+		{"duration", gfmt.Column{Unit: gfmt.UnitDuration}, 90e9, "1m30s"},
+		{"duration_value", gfmt.Column{Unit: gfmt.UnitDuration, Thousands: true}, 1500 * time.Millisecond, "1.5s"},
+		{"unix", gfmt.Column{Unit: gfmt.UnitTime}, 1700000000, "2023-11-14T22:13:20Z"},
+		{"unix_zone", gfmt.Column{Unit: gfmt.UnitTime, Location: vienna, TimeLayout: time.DateTime}, "1700000000", "2023-11-14 23:13:20"},
+		{"time_string", gfmt.Column{Unit: gfmt.UnitTime, TimeLayout: time.DateOnly}, "2023-11-14T22:13:20Z", "2023-11-14"},
+		{"time_value", gfmt.Column{Location: time.UTC}, time.Date(2023, 1, 2, 3, 4, 5, 0, vienna), "2023-01-02T02:04:05Z"},
+		{"relative", gfmt.Column{Relative: true}, time.Now().Add(-3*time.Hour - time.Minute), "3 hours ago"},
+		{"relative_future", gfmt.Column{Unit: gfmt.UnitTime, Relative: true}, time.Now().Add(49 * time.Hour).Unix(), "in 2 days"},
+		{"unchanged", gfmt.Column{Unit: gfmt.UnitBytes}, "n/a", "n/a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &strings.Builder{}
+			_, err := gfmt.NewTab(b, gfmt.WithColumn("v", tt.col)).Write(newOrderedMap("v", tt.val))
+			require.NoError(t, err)
+			require.Equal(t, tt.want, strings.TrimSpace(strings.TrimPrefix(b.String(), "v")))
+		})
+	}
+}
@@ -0,0 +1,347 @@
+// Copyright 2026 The gutenfmt authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gfmt
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/abc-inc/gutenfmt/internal/render"
+	"github.com/abc-inc/gutenfmt/internal/table"
+)
+
+// Compare determines how the values of a sort key are compared.
+type Compare int
+
+const (
+	// CompareAuto compares numbers numerically, times chronologically and anything else lexically.
+	CompareAuto Compare = iota
+	// CompareLexical compares the string representations byte-wise.
+	CompareLexical
+	// CompareNumeric compares numbers and strings holding numbers by their numeric value.
+	CompareNumeric
+	// CompareNatural compares strings with embedded numbers e.g., versions, in natural order,
+	// i.e., "v1.9" sorts before "v1.10".
+	CompareNatural
+	// CompareTime compares times and strings holding times in common layouts like RFC 3339.
+	CompareTime
+)
+
+var compareNames = map[string]Compare{
+	"auto":    CompareAuto,
+	"lexical": CompareLexical,
+	"numeric": CompareNumeric,
+	"natural": CompareNatural,
+	"time":    CompareTime,
+}
+
+// timeLayouts holds the layouts tried when comparing strings as times.
+var timeLayouts = []string{time.RFC3339Nano, time.DateTime, time.DateOnly, time.RFC1123Z, time.RFC1123, time.RFC822Z, time.RFC822}
+
+// SortKey defines a field to sort records by.
+type SortKey struct {
+	// Field is the map key or the name of the struct field, as determined by meta.Resolve.
+	Field string
+	// Desc sorts in descending order.
+	Desc bool
+	// Compare determines how the values are compared.
+	Compare Compare
+}
+
+// ParseSortKeys parses a comma-separated list of sort keys.
+// Each key is a field name, optionally prefixed by "-" for descending or "+"
+// for ascending order, and followed by a colon and one of "auto", "lexical",
+// "numeric", "natural" or "time" e.g., "-age:numeric,name".
+func ParseSortKeys(s string) ([]SortKey, error) {
+	var ks []SortKey
+	for _, f := range strings.Split(s, ",") {
+		n, c, _ := strings.Cut(strings.TrimSpace(f), ":")
+		k := SortKey{}
+		if strings.HasPrefix(n, "-") {
+			k.Desc = true
+		}
+		if k.Field = strings.TrimLeft(n, "+-"); k.Field == "" {
+			return nil, fmt.Errorf("invalid sort key: %q", f)
+		}
+		if c != "" {
+			var ok bool
+			if k.Compare, ok = compareNames[strings.ToLower(c)]; !ok {
+				return nil, fmt.Errorf("invalid comparison in sort key: %q", f)
+			}
+		}
+		ks = append(ks, k)
+	}
+	return ks, nil
+}
+
+// Rows is a Writer that sorts the elements of a slice or array and selects
+// a range of them, before passing them to the delegate Writer.
+// Other values are passed as is.
+//
+// Elements are sorted by the fields of records. Elements, which are not
+// records, are compared by their own value. Nil values and missing fields
+// are always sorted last.
+type Rows struct {
+	writer Writer
+	// Keys holds the sort keys in order of precedence.
+	Keys []SortKey
+	// Offset is the number of elements to skip.
+	Offset int
+	// Limit is the maximum number of elements to write, 0 means unlimited.
+	Limit int
+}
+
+// NewRows creates a new Writer that sorts, skips and limits elements.
+func NewRows(delegate Writer, opts ...Opt[Rows]) *Rows {
+	w := &Rows{writer: delegate}
+	for _, opt := range opts {
+		opt(w)
+	}
+	return w
+}
+
+// Write sorts and slices i and writes the result to the delegate Writer.
+func (w Rows) Write(i any) (int, error) {
+	v := reflect.ValueOf(i)
+	if k := v.Kind(); k != reflect.Slice && k != reflect.Array {
+		return w.writer.Write(i)
+	}
+
+	idxs := make([]int, v.Len())
+	for idx := range idxs {
+		idxs[idx] = idx
+	}
+
+	if len(w.Keys) > 0 {
+		vals := make([][]any, len(idxs))
+		for idx := range vals {
+			vals[idx] = w.values(v.Index(idx).Interface())
+		}
+		sort.SliceStable(idxs, func(a, b int) bool {
+			return w.less(vals[idxs[a]], vals[idxs[b]])
+		})
+	}
+
+	idxs = idxs[min(max(w.Offset, 0), len(idxs)):]
+	if w.Limit > 0 && w.Limit < len(idxs) {
+		idxs = idxs[:w.Limit]
+	}
+
+	// Keep the type of the elements, so that e.g., struct slices still have a header.
+	typ := v.Type()
+	if v.Kind() == reflect.Array {
+		typ = reflect.SliceOf(typ.Elem())
+	}
+	es := reflect.MakeSlice(typ, len(idxs), len(idxs))
+	for idx, o := range idxs {
+		es.Index(idx).Set(v.Index(o))
+	}
+	return w.writer.Write(es.Interface())
+}
+
+// values returns the values of all sort keys of an element.
+func (w Rows) values(e any) []any {
+	vs := make([]any, len(w.Keys))
+	r, ok := table.Record(e, table.Options{})
+	for idx, k := range w.Keys {
+		if ok {
+			vs[idx] = lookupField(r, k.Field)
+		} else {
+			vs[idx] = e
+		}
+	}
+	return vs
+}
+
+// less reports whether the values a must sort before the values b.
+func (w Rows) less(a, b []any) bool {
+	for idx, k := range w.Keys {
+		an, bn := isNil(a[idx]), isNil(b[idx])
+		if an || bn {
+			if an != bn {
+				return bn
+			}
+			continue
+		}
+		c := compare(k.Compare, a[idx], b[idx])
+		if k.Desc {
+			c = -c
+		}
+		if c != 0 {
+			return c < 0
+		}
+	}
+	return false
+}
+
+// compare returns -1, 0 or +1 depending on whether a is less than, equal to, or greater than b.
+//
+// Values, which cannot be compared numerically or as time, sort after those
+// which can and are compared lexically among each other.
+func compare(c Compare, a, b any) int {
+	switch c {
+	case CompareAuto:
+		if _, aok := toFloat(a); aok {
+			if _, bok := toFloat(b); bok {
+				return compare(CompareNumeric, a, b)
+			}
+		}
+		if _, aok := a.(time.Time); aok {
+			return compare(CompareTime, a, b)
+		}
+	case CompareNumeric:
+		af, aok := toFloat(a)
+		bf, bok := toFloat(b)
+		if aok && bok {
+			return cmp.Compare(af, bf)
+		} else if aok != bok {
+			return cmpBool(bok, aok)
+		}
+	case CompareTime:
+		at, aok := toTime(a)
+		bt, bok := toTime(b)
+		if aok && bok {
+			return at.Compare(bt)
+		} else if aok != bok {
+			return cmpBool(bok, aok)
+		}
+	case CompareNatural:
+		return compareNatural(render.ToString(a), render.ToString(b))
+	}
+	return strings.Compare(render.ToString(a), render.ToString(b))
+}
+
+// compareNatural compares strings chunk by chunk, where consecutive digits
+// are compared by their numeric value.
+func compareNatural(a, b string) int {
+	for a != "" && b != "" {
+		ac, ar := chunk(a)
+		bc, br := chunk(b)
+		if isDigit(ac[0]) && isDigit(bc[0]) {
+			an, bn := strings.TrimLeft(ac, "0"), strings.TrimLeft(bc, "0")
+			if c := cmp.Compare(len(an), len(bn)); c != 0 {
+				return c
+			} else if c = strings.Compare(an, bn); c != 0 {
+				return c
+			}
+		} else if c := strings.Compare(ac, bc); c != 0 {
+			return c
+		}
+		a, b = ar, br
+	}
+	return cmp.Compare(len(a), len(b))
+}
+
+// chunk splits s after the leading run of digits or non-digits.
+func chunk(s string) (string, string) {
+	d := isDigit(s[0])
+	idx := 1
+	for idx < len(s) && isDigit(s[idx]) == d {
+		idx++
+	}
+	return s[:idx], s[idx:]
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isNil(i any) bool {
+	if i == nil {
+		return true
+	}
+	v := reflect.ValueOf(i)
+	switch v.Kind() { //nolint:exhaustive
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface:
+		return v.IsNil()
+	}
+	return false
+}
+
+// toFloat converts numbers and strings holding numbers to float64.
+func toFloat(i any) (float64, bool) {
+	switch x := i.(type) {
+	case json.Number:
+		f, err := x.Float64()
+		return f, err == nil
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(x), 64)
+		return f, err == nil
+	}
+	v := reflect.ValueOf(i)
+	switch v.Kind() { //nolint:exhaustive
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	}
+	return 0, false
+}
+
+// toTime converts times and strings holding times to time.Time.
+func toTime(i any) (time.Time, bool) {
+	switch x := i.(type) {
+	case time.Time:
+		return x, true
+	case *time.Time:
+		return *x, true
+	case string:
+		for _, l := range timeLayouts {
+			if t, err := time.Parse(l, strings.TrimSpace(x)); err == nil {
+				return t, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// cmpBool orders false before true.
+func cmpBool(a, b bool) int {
+	if a == b {
+		return 0
+	} else if a {
+		return 1
+	}
+	return -1
+}
+
+// WithSortBy sets the sort keys of a Rows Writer.
+func WithSortBy(ks ...SortKey) Opt[Rows] {
+	return func(w *Rows) {
+		w.Keys = ks
+	}
+}
+
+// WithOffset sets the number of elements a Rows Writer skips.
+func WithOffset(n int) Opt[Rows] {
+	return func(w *Rows) {
+		w.Offset = n
+	}
+}
+
+// WithLimit sets the maximum number of elements a Rows Writer writes.
+func WithLimit(n int) Opt[Rows] {
+	return func(w *Rows) {
+		w.Limit = n
+	}
+}
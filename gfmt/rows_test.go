@@ -0,0 +1,127 @@
+// Copyright 2026 The gutenfmt authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gfmt_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abc-inc/gutenfmt/gfmt"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSortKeys(t *testing.T) {
+	ks, err := gfmt.ParseSortKeys("-age:numeric, +name,version:Natural")
+	require.NoError(t, err)
+	require.Equal(t, []gfmt.SortKey{
+		{Field: "age", Desc: true, Compare: gfmt.CompareNumeric},
+		{Field: "name"},
+		{Field: "version", Compare: gfmt.CompareNatural},
+	}, ks)
+
+	_, err = gfmt.ParseSortKeys("-")
+	require.Error(t, err)
+	_, err = gfmt.ParseSortKeys("a:random")
+	require.Error(t, err)
+}
+
+func TestRows_Write(t *testing.T) {
+	ms := []any{
+		newOrderedMap("name", "b", "age", 30),
+		newOrderedMap("name", "a", "age", 4),
+		newOrderedMap("name", "c", "age", 30),
+		newOrderedMap("name", "d"),
+	}
+
+	b := &strings.Builder{}
+	w := gfmt.NewRows(gfmt.NewTab(b), gfmt.WithSortBy(gfmt.SortKey{Field: "age", Desc: true}, gfmt.SortKey{Field: "name"}))
+	_, err := w.Write(ms)
+	require.NoError(t, err)
+	require.Equal(t, "name age \nb    30  \nc    30  \na    4   \nd    ", b.String())
+
+	b.Reset()
+	w.Offset, w.Limit = 1, 2
+	_, err = w.Write(ms)
+	require.NoError(t, err)
+	require.Equal(t, "name age \nc    30  \na    4", b.String())
+
+	b.Reset()
+	w.Offset = 10
+	_, err = w.Write(ms)
+	require.NoError(t, err)
+	require.Equal(t, "", b.String())
+}
+
+func TestRows_WriteCompare(t *testing.T) {
+	tests := []struct {
+		name string
+		c    gfmt.Compare
+		in   []any
+		want string
+	}{
+		{"auto_numbers", gfmt.CompareAuto, []any{10, 9.5, -1}, "[-1,9.5,10]"},
+		{"auto_strings", gfmt.CompareAuto, []any{"b", "B", "a"}, `["B","a","b"]`},
+		{"lexical", gfmt.CompareLexical, []any{10, 9, 100}, "[10,100,9]"},
+		{"numeric", gfmt.CompareNumeric, []any{"10", "x", "9", "1e1"}, `["9","10","1e1","x"]`},
+		{"natural", gfmt.CompareNatural, []any{"v1.10.0", "v1.9.2", "v1.9.10", "v01.9.3"}, `["v1.9.2","v01.9.3","v1.9.10","v1.10.0"]`},
+		{"time", gfmt.CompareTime, []any{"2026-01-02T00:00:00+02:00", "2026-01-01 23:00:00", "2025-12-31"},
+			`["2025-12-31","2026-01-02T00:00:00+02:00","2026-01-01 23:00:00"]`},
+		{"time_values", gfmt.CompareAuto, []any{time.Unix(20, 0).UTC(), time.Unix(10, 0).UTC()},
+			`["1970-01-01T00:00:10Z","1970-01-01T00:00:20Z"]`},
+		{"nil_last", gfmt.CompareAuto, []any{nil, 2, 1}, "[1,2,null]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &strings.Builder{}
+			_, err := gfmt.NewRows(gfmt.NewJSON(b), gfmt.WithSortBy(gfmt.SortKey{Field: "x", Compare: tt.c})).Write(tt.in)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, b.String())
+		})
+	}
+}
+
+func TestRows_WriteStructs(t *testing.T) {
+	us := []User{*NewUser("Jane", "Doe"), *NewUser("John", "Doe"), *NewUser("Alice", "Roe")}
+
+	b := &strings.Builder{}
+	w := gfmt.NewRows(gfmt.NewTab(b), gfmt.WithSortBy(gfmt.SortKey{Field: "username", Desc: true}))
+	_, err := w.Write(us)
+	require.NoError(t, err)
+	require.Equal(t, "username  email          \nJohn Doe  john.doe@local \nJane Doe  jane.doe@local \nAlice Roe alice.roe@local", b.String())
+
+	b.Reset()
+	w.Offset = 10
+	_, err = w.Write(us)
+	require.NoError(t, err)
+	require.Equal(t, "username email", b.String())
+
+	b.Reset()
+	w = gfmt.NewRows(gfmt.NewText(b), gfmt.WithSortBy(gfmt.SortKey{Field: "username", Desc: true}), gfmt.WithLimit(1))
+	_, err = w.Write([]*User{NewUser("Jane", "Doe"), NewUser("John", "Doe")})
+	require.NoError(t, err)
+	require.Equal(t, "username:email\nJohn Doe:john.doe@local", b.String())
+
+	b.Reset()
+	_, err = w.Write([2]User{us[0], us[2]})
+	require.NoError(t, err)
+	require.Equal(t, "username:email\nJane Doe:jane.doe@local", b.String())
+
+	b.Reset()
+	_, err = w.Write("scalar")
+	require.NoError(t, err)
+	require.Equal(t, "scalar", b.String())
+}
@@ -19,21 +19,89 @@ import (
 	"fmt"
 	"io"
 	"reflect"
+	"strings"
 	"text/tabwriter"
+	"time"
 
 	"github.com/abc-inc/gutenfmt/formatter"
 	"github.com/abc-inc/gutenfmt/internal/render"
+	"github.com/abc-inc/gutenfmt/internal/table"
+	"github.com/abc-inc/gutenfmt/internal/width"
+	"github.com/alecthomas/chroma/v2"
 )
 
+// Overflow determines how cells exceeding the maximum width of their column are shortened.
+type Overflow int
+
+const (
+	// OverflowTruncate cuts the cell and appends an ellipsis.
+	OverflowTruncate Overflow = iota
+	// OverflowWrap breaks the cell into multiple lines, preferably at spaces.
+	OverflowWrap
+)
+
+// Column controls the layout of a table column and the presentation of its values.
+type Column struct {
+	// MaxWidth is the maximum display width of the cells, 0 means unlimited.
+	MaxWidth int
+	// Overflow determines how longer cells are shortened.
+	Overflow Overflow
+	// Align determines the horizontal alignment of the cells.
+	Align Align
+	// Thousands groups the digits of numbers e.g., "1,234,567".
+	Thousands bool
+	// Precision is the fixed number of decimals of numbers, nil keeps them as is.
+	Precision *int
+	// Unit determines how numbers are interpreted e.g., as bytes or durations.
+	Unit Unit
+	// TimeLayout formats times (see time.Layout), time.RFC3339 if empty.
+	TimeLayout string
+	// Relative renders times relative to now e.g., "3 hours ago".
+	Relative bool
+	// Location converts times to the time zone, nil keeps the time zone.
+	// Unix timestamps are in UTC, unless a Location is given.
+	Location *time.Location
+}
+
+// ellipsis is appended to truncated cells.
+const ellipsis = "…"
+
 // Tab is a generic Writer that formats arbitrary values as ASCII table.
+//
+// Columns are aligned according to the display width of the cells, i.e.,
+// wide characters occupy two columns and ANSI escape sequences are ignored.
 type Tab struct {
 	cw        *countingWriter
 	Formatter *formatter.CompFormatter
+	// SortKeys sorts the keys of maps, which do not define an order.
+	SortKeys bool
+	// Flatten expands nested values of records into separate columns, if not nil.
+	Flatten *Flatten
+	// Missing is the placeholder for cells, whose record does not have the column's key.
+	Missing string
+	// TableStyle determines the borders and separators of the table.
+	TableStyle TableStyle
+	// Style colors headers, names, borders and values, nil disables colors.
+	Style *chroma.Style
+	// Highlights colors the cells or rows of records, which match a condition.
+	Highlights []Highlight
+	// Columns limits the width of columns by name.
+	// Name and value pairs are in columns called "Name" and "Value".
+	// The entry with the empty name applies to all other columns.
+	Columns map[string]Column
+	// Vertical prints records as blocks of name and value lines instead of rows.
+	Vertical Vertical
+	// Width is the available width e.g., of the terminal, 0 means unknown.
+	Width int
 }
 
 // NewTab creates a new table Writer.
-func NewTab(w io.Writer) *Tab {
-	return &Tab{wrapCountingWriter(w), formatter.NewComp()}
+func NewTab(w io.Writer, opts ...Opt[Tab]) *Tab {
+	gw := &Tab{cw: wrapCountingWriter(w), Formatter: formatter.NewComp(), TableStyle: StylePlain}
+	for _, opt := range opts {
+		opt(gw)
+	}
+	return gw
 }
 
 // Write formats the given value as a table and writes it to the underlying Writer.
@@ -48,6 +116,25 @@ func (w Tab) Write(i any) (int, error) {
 		return 0, err
 	}
 
+	if w.Flatten != nil {
+		c := w
+		c.Flatten = nil
+		return c.Write(w.Flatten.flatten(i, w.SortKeys))
+	}
+
+	if len(w.Highlights) > 0 || w.presents() {
+		c := w
+		v, cols := w.present(i)
+		c.Highlights, c.Columns = nil, cols
+		return c.Write(v)
+	}
+
+	if p := newPainter(w.Style, w.SortKeys); p != nil {
+		c := w
+		c.Style, c.TableStyle.paint = nil, p.punctuation
+		return c.Write(p.colorize(i))
+	}
+
 	typ := reflect.TypeOf(i)
 	if typ.Kind() == reflect.Ptr {
 		return w.Write(reflect.Indirect(reflect.ValueOf(i)).Interface())
@@ -55,72 +142,300 @@ func (w Tab) Write(i any) (int, error) {
 		return fmt.Fprint(w.cw, i)
 	}
 
-	w.cw.cnt = 0
-	tw := tabwriter.NewWriter(w.cw, 4, 4, 1, ' ', 0)
+	if t := w.vertical(i); t != nil {
+		return w.writeVertical(t)
+	}
+	if !w.TableStyle.plain {
+		return w.writeStyled(i)
+	}
+
+	if formatter.IsMap(i) {
+		return w.writeMap(i)
+	}
 
 	switch typ.Kind() { //nolint:exhaustive
 	case reflect.Slice, reflect.Array:
-		_, err := w.writeSlice(tw, reflect.ValueOf(i))
-		return w.cw.cnt, err
-	case reflect.Map:
-		_, err := w.writeMap(tw, i)
-		return w.cw.cnt, err
+		return w.writeSlice(reflect.ValueOf(i))
 	default:
-		_, err := w.writeStruct(tw, i)
-		return w.cw.cnt, err
+		return w.writeStruct(i)
 	}
 }
 
 // writeSlice formats a slice of any type to a string.
-func (w Tab) writeSlice(tw *tabwriter.Writer, v reflect.Value) (int, error) {
+func (w Tab) writeSlice(v reflect.Value) (int, error) {
 	if v.Type().Elem().Kind() == reflect.Struct ||
 		(v.Type().Elem().Kind() == reflect.Ptr && v.Type().Elem().Elem().Kind() == reflect.Struct) {
 
-		return w.writeStructSlice(tw, v)
+		return w.writeStructSlice(v)
 	}
 	if v.Len() == 0 {
 		return 0, nil
 	}
-	if typ := reflect.TypeOf(reflect.Indirect(v.Index(0)).Interface()); typ != nil && typ.Kind() == reflect.Map {
-		return w.writeMapSlice(tw, v)
+	if formatter.IsMap(v.Index(0).Interface()) {
+		return w.writeMapSlice(v)
 	}
 
-	n, err := w.cw.WriteString(render.ToString(v.Index(0).Interface()))
-	if err != nil {
-		return n, err
-	}
-	cnt := n
-
-	for idx := 1; idx < v.Len(); idx++ {
-		n, err = w.cw.WriteString("\n" + render.ToString(v.Index(idx).Interface()))
-		cnt += n
-		if err != nil {
-			return cnt, err
-		}
+	ls := make([]string, v.Len())
+	for idx := range ls {
+		ls[idx] = render.ToString(v.Index(idx).Interface())
 	}
-	return cnt, tw.Flush()
+	return w.align(strings.Join(ls, "\n"), false)
 }
 
 // writeMap formats a map to a tabular string representation.
-func (w Tab) writeMap(tw *tabwriter.Writer, i any) (int, error) {
+func (w Tab) writeMap(i any) (int, error) {
 	f := formatter.FromMap("\t", "\t\n")
-	return formatter.FormatTab(tw, f, i)
+	if w.SortKeys {
+		f = formatter.FromMapSorted("\t", "\t\n")
+	}
+	return w.format(f, i, true)
 }
 
 // writeMapSlice formats a map slice to a tabular string representation.
-func (w Tab) writeMapSlice(tw *tabwriter.Writer, v reflect.Value) (int, error) {
-	f := formatter.FromMapSlice("\t", "\t\n")
-	return formatter.FormatTab(tw, f, v.Interface())
+func (w Tab) writeMapSlice(v reflect.Value) (int, error) {
+	ks := formatter.MapSliceKeys(v.Interface(), w.SortKeys)
+	f := formatter.FromMapSliceKeysMissing("\t", "\t\n", w.Missing, ks...)
+	return w.format(f, v.Interface(), false)
 }
 
 // writeStruct formats a struct to a tabular string representation.
-func (w Tab) writeStruct(tw *tabwriter.Writer, i any) (int, error) {
+func (w Tab) writeStruct(i any) (int, error) {
 	f := formatter.FromStruct("\t", "\t\n", reflect.TypeOf(i))
-	return formatter.FormatTab(tw, f, i)
+	return w.format(f, i, true)
 }
 
 // writeStructSlice formats a struct slice to a tabular string representation.
-func (w Tab) writeStructSlice(tw *tabwriter.Writer, v reflect.Value) (int, error) {
+func (w Tab) writeStructSlice(v reflect.Value) (int, error) {
 	f := formatter.FromStructSlice("\t", "\t\n", v.Type())
-	return formatter.FormatTab(tw, f, v.Interface())
+	return w.format(f, v.Interface(), false)
+}
+
+// format formats i as tab-separated cells and aligns them.
+func (w Tab) format(f formatter.Formatter, i any, kv bool) (int, error) {
+	s, err := f.Format(i)
+	if err != nil {
+		return 0, err
+	}
+	return w.align(s, kv)
+}
+
+// align aligns the tab-terminated cells of s and writes the result.
+//
+// The cells are measured by their display width.
+// If kv is true, the cells are name and value pairs. Otherwise, the first
+// line holds the column names.
+func (w Tab) align(s string, kv bool) (int, error) {
+	var ls [][]string
+	for _, l := range strings.Split(s, "\n") {
+		ls = append(ls, strings.Split(l, "\t"))
+	}
+
+	names := []string{"Name", "Value"}
+	if !kv {
+		names = ls[0][:len(ls[0])-1]
+	}
+	aligns, aligned := make([]Align, len(names)), false
+	for idx, n := range names {
+		aligns[idx] = w.column(width.Strip(n)).Align
+		aligned = aligned || aligns[idx] == AlignRight || aligns[idx] == AlignCenter
+	}
+	if aligned && len(ls[len(ls)-1]) == len(names) {
+		// Terminate the last cell, so that it is part of its column.
+		ls[len(ls)-1] = append(ls[len(ls)-1], "")
+	}
+
+	// Shorten the cells, which may result in multiple lines per row.
+	var cls [][]string
+	for _, l := range ls {
+		cs := make([][]string, len(l))
+		n := 1
+		for idx, c := range l {
+			name := ""
+			if idx < len(names) {
+				name = names[idx]
+			}
+			cs[idx] = w.shorten(name, c)
+			n = max(n, len(cs[idx]))
+		}
+		for ln := 0; ln < n; ln++ {
+			cl := make([]string, len(cs))
+			for idx, c := range cs {
+				if ln < len(c) {
+					cl[idx] = c[ln]
+				}
+			}
+			cls = append(cls, cl)
+		}
+	}
+
+	return w.cw.WriteString(aligner{aligns: aligns}.align(cls))
+}
+
+// shorten limits the width of a cell in the named column.
+// It returns the lines of the cell.
+func (w Tab) shorten(name, c string) []string {
+	col := w.column(width.Strip(name))
+	if col.MaxWidth <= 0 || width.String(c) <= col.MaxWidth {
+		return []string{c}
+	} else if col.Overflow == OverflowWrap {
+		return width.Wrap(c, col.MaxWidth)
+	}
+	return []string{width.Truncate(c, col.MaxWidth, ellipsis)}
+}
+
+// placeholder stands in for the cells passed to text/tabwriter.
+const placeholder = 'x'
+
+// aligner pads tab-terminated cells to the width of their column.
+//
+// The layout is done by text/tabwriter with a minimum width of 4 and a padding
+// of 1. Since text/tabwriter counts runes, each cell is replaced by as many
+// placeholders as it is wide on the screen, plus one marking the start of the
+// cell. Each line consists of cells and trailing text, which is not part of
+// any column.
+type aligner struct {
+	aligns []Align
+}
+
+// align aligns the cells and joins the lines.
+func (a aligner) align(lines [][]string) string {
+	b := &strings.Builder{}
+	tw := tabwriter.NewWriter(b, 4+1, 0, 1, ' ', 0)
+	for _, l := range lines {
+		for _, c := range l[:len(l)-1] {
+			_, _ = tw.Write([]byte(strings.Repeat(string(placeholder), width.String(c)+1) + "\t"))
+		}
+		_, _ = tw.Write([]byte("\n"))
+	}
+	_ = tw.Flush()
+
+	ps := strings.Split(b.String(), "\n")
+	b.Reset()
+	for i, l := range lines {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		p, pos := ps[i], 0
+		for j, c := range l[:len(l)-1] {
+			pos += width.String(c) + 1
+			n := pos
+			for n < len(p) && p[n] != placeholder {
+				n++
+			}
+			al := AlignLeft
+			if j < len(a.aligns) {
+				al = a.aligns[j]
+			}
+			b.WriteString(pad(c, width.String(c)+n-pos-1, al) + " ")
+			pos = n
+		}
+		b.WriteString(l[len(l)-1])
+	}
+	return b.String()
+}
+
+// pad pads a cell to the given display width according to the alignment.
+func pad(c string, n int, a Align) string {
+	n = max(n-width.String(c), 0)
+	switch a { //nolint:exhaustive
+	case AlignRight:
+		return strings.Repeat(" ", n) + c
+	case AlignCenter:
+		return strings.Repeat(" ", n/2) + c + strings.Repeat(" ", n-n/2)
+	default:
+		return c + strings.Repeat(" ", n)
+	}
+}
+
+// writeStyled draws the table using the characters of the TableStyle.
+func (w Tab) writeStyled(i any) (int, error) {
+	t, ok := table.From(i, table.Options{SortKeys: w.SortKeys, Missing: w.Missing})
+	if !ok || (len(t.Header) == 0 && len(t.Rows) == 0) {
+		return 0, nil
+	}
+
+	names := t.Header
+	if names == nil && len(t.Rows[0]) == 2 {
+		names = []string{"Name", "Value"}
+	}
+
+	rows := make([][][]string, 0, len(t.Rows)+1)
+	if t.Header != nil {
+		rows = append(rows, w.shortenRow(names, t.Header))
+	}
+	for _, r := range t.Rows {
+		cs := make([]string, len(r))
+		for idx, c := range r {
+			cs[idx] = render.ToString(c)
+		}
+		rows = append(rows, w.shortenRow(names, cs))
+	}
+
+	ws := make([]int, len(rows[0]))
+	for _, r := range rows {
+		for idx, c := range r {
+			for _, l := range c {
+				ws[idx] = max(ws[idx], width.String(l))
+			}
+		}
+	}
+
+	for idx, n := range names {
+		if a := w.column(width.Strip(n)).Align; a == AlignRight || a == AlignCenter {
+			for _, r := range rows {
+				for ln, l := range r[idx] {
+					r[idx][ln] = pad(l, ws[idx], a)
+				}
+			}
+		}
+	}
+
+	s := w.TableStyle
+	b := &strings.Builder{}
+	if s.Top.Fill != "" {
+		s.line(b, s.Top, ws)
+	}
+	for idx, r := range rows {
+		s.row(b, r, ws)
+		if idx == 0 && t.Header != nil && s.Header.Fill != "" {
+			s.line(b, s.Header, ws)
+		}
+	}
+	if s.Bottom.Fill != "" {
+		s.line(b, s.Bottom, ws)
+	}
+	return w.cw.WriteString(strings.TrimSuffix(b.String(), "\n"))
+}
+
+// shortenRow splits the cells of a row into lines and limits their width.
+func (w Tab) shortenRow(names, cs []string) [][]string {
+	r := make([][]string, len(cs))
+	for idx, c := range cs {
+		name := ""
+		if idx < len(names) {
+			name = names[idx]
+		}
+		for _, l := range strings.Split(c, "\n") {
+			r[idx] = append(r[idx], w.shorten(name, l)...)
+		}
+	}
+	return r
+}
+
+// WithTableStyle sets the TableStyle of a Tab Writer.
+func WithTableStyle(s TableStyle) Opt[Tab] {
+	return func(w *Tab) {
+		w.TableStyle = s
+	}
+}
+
+// WithMaxWidth limits the width of the named column of a Tab Writer.
+// If name is empty, the limit applies to all columns without their own limit.
+func WithMaxWidth(name string, maxWidth int, o Overflow) Opt[Tab] {
+	return func(w *Tab) {
+		if w.Columns == nil {
+			w.Columns = map[string]Column{}
+		}
+		w.Columns[name] = Column{MaxWidth: maxWidth, Overflow: o}
+	}
 }
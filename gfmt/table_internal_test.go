@@ -0,0 +1,58 @@
+// Copyright 2026 The gutenfmt authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gfmt
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_aligner_align(t *testing.T) {
+	tests := []struct {
+		name   string
+		aligns []Align
+		lines  [][]string
+		want   string
+	}{
+		{"ascii", nil,
+			[][]string{{"a", "bb", ""}, {"ccccc", "d", ""}},
+			"a     bb  \nccccc d   "},
+		{"wide_runes", nil,
+			[][]string{{"日本語", "x", ""}, {"ab", "y", ""}},
+			"日本語 x   \nab     y   "},
+		{"ansi", nil,
+			[][]string{{"\x1b[31mred\x1b[0m", "x", ""}, {"green", "y", ""}},
+			"\x1b[31mred\x1b[0m   x   \ngreen y   "},
+		{"empty_cells", nil,
+			[][]string{{"", "", ""}, {"a", "", "b"}},
+			"        \na       b"},
+		{"ragged_rows", nil,
+			[][]string{{"a", "b", "c", ""}, {"dddddd", ""}, {"e", "f", ""}},
+			"a      b   c   \ndddddd \ne      f   "},
+		{"trailing_text", nil,
+			[][]string{{"a", "trailing text"}, {"bb", ""}},
+			"a   trailing text\nbb  "},
+		{"right_center", []Align{AlignRight, AlignCenter},
+			[][]string{{"a", "b", ""}, {"日本", "ccccc", ""}},
+			"   a   b   \n日本 ccccc "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, aligner{aligns: tt.aligns}.align(tt.lines))
+		})
+	}
+}
@@ -150,3 +150,124 @@ func TestTab_WriteMapSliceCustom(t *testing.T) {
 	require.NoError(t, err)
 	require.Regexp(t, "(c   d   \n1   2   \n3   4)|(d   c   \n2   1   \n4   3)", b.String())
 }
+
+func TestTab_WriteOrderedMap(t *testing.T) {
+	b := &strings.Builder{}
+	_, err := gfmt.NewTab(b).Write(newOrderedMap("z", 1, "long", "a"))
+	require.NoError(t, err)
+	require.Equal(t, "z    1   \nlong a   \n", b.String())
+
+	b.Reset()
+	_, err = gfmt.NewTab(b).Write([]any{newOrderedMap("z", 1, "a", 2), newOrderedMap("a", 3, "z", 4)})
+	require.NoError(t, err)
+	require.Equal(t, "z   a   \n1   2   \n4   3", b.String())
+}
+
+func TestTab_WriteSortKeys(t *testing.T) {
+	b := &strings.Builder{}
+	_, err := gfmt.NewTab(b, gfmt.WithSortKeys[gfmt.Tab]()).Write([]map[string]int{{"c": 1, "a": 2}, {"a": 3, "c": 4}})
+	require.NoError(t, err)
+	require.Equal(t, "a   c   \n2   1   \n3   4", b.String())
+}
+
+func TestTab_WriteTableStyle(t *testing.T) {
+	users := []any{newOrderedMap("name", "∮∯∰", "age", 42), newOrderedMap("name", "Jo", "age", 7)}
+	tests := []struct {
+		style gfmt.TableStyle
+		want  string
+	}{
+		{gfmt.StyleASCII, "+------+-----+\n| name | age |\n+------+-----+\n| ∮∯∰  | 42  |\n| Jo   | 7   |\n+------+-----+"},
+		{gfmt.StyleLight, "┌──────┬─────┐\n│ name │ age │\n├──────┼─────┤\n│ ∮∯∰  │ 42  │\n│ Jo   │ 7   │\n└──────┴─────┘"},
+		{gfmt.StyleHeavy, "┏━━━━━━┳━━━━━┓\n┃ name ┃ age ┃\n┣━━━━━━╋━━━━━┫\n┃ ∮∯∰  ┃ 42  ┃\n┃ Jo   ┃ 7   ┃\n┗━━━━━━┻━━━━━┛"},
+		{gfmt.StyleRounded, "╭──────┬─────╮\n│ name │ age │\n├──────┼─────┤\n│ ∮∯∰  │ 42  │\n│ Jo   │ 7   │\n╰──────┴─────╯"},
+		{gfmt.StyleCompact, "name  age\n----  ---\n∮∯∰   42\nJo    7"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.style.Name, func(t *testing.T) {
+			b := &strings.Builder{}
+			_, err := gfmt.NewTab(b, gfmt.WithTableStyle(tt.style)).Write(users)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, b.String())
+		})
+	}
+}
+
+func TestTab_WriteTableStyleKeyValue(t *testing.T) {
+	b := &strings.Builder{}
+	_, err := gfmt.NewTab(b, gfmt.WithTableStyle(gfmt.StyleASCII)).Write(newOrderedMap("z", 1, "long", "a"))
+	require.NoError(t, err)
+	require.Equal(t, "+------+---+\n| z    | 1 |\n| long | a |\n+------+---+", b.String())
+
+	b.Reset()
+	_, err = gfmt.NewTab(b, gfmt.WithTableStyle(gfmt.StyleCompact)).Write([]string{"x", "yy"})
+	require.NoError(t, err)
+	require.Equal(t, "x\nyy", b.String())
+}
+
+func TestLookupTableStyle(t *testing.T) {
+	s, ok := gfmt.LookupTableStyle("Rounded")
+	require.True(t, ok)
+	require.Equal(t, gfmt.StyleRounded, s)
+
+	_, ok = gfmt.LookupTableStyle("unknown")
+	require.False(t, ok)
+	require.Equal(t, []string{"ascii", "compact", "heavy", "light", "plain", "rounded"}, gfmt.TableStyleNames())
+}
+
+func TestTab_WriteDisplayWidth(t *testing.T) {
+	b := &strings.Builder{}
+	_, err := gfmt.NewTab(b).Write([]any{newOrderedMap("name", "日本語", "n", 1), newOrderedMap("name", "\x1b[31mred\x1b[0m", "n", 2)})
+	require.NoError(t, err)
+	require.Equal(t, "name   n   \n日本語 1   \n\x1b[31mred\x1b[0m    2", b.String())
+
+	b.Reset()
+	_, err = gfmt.NewTab(b, gfmt.WithTableStyle(gfmt.StyleASCII)).Write(newOrderedMap("emoji", "👍", "text", "ab"))
+	require.NoError(t, err)
+	require.Equal(t, "+-------+----+\n| emoji | 👍 |\n| text  | ab |\n+-------+----+", b.String())
+}
+
+func TestTab_WriteMaxWidth(t *testing.T) {
+	users := []any{
+		newOrderedMap("name", "Alexander", "bio", "likes long walks on the beach"),
+		newOrderedMap("name", "Jo", "bio", "-"),
+	}
+
+	b := &strings.Builder{}
+	w := gfmt.NewTab(b, gfmt.WithMaxWidth("", 5, gfmt.OverflowTruncate), gfmt.WithMaxWidth("bio", 12, gfmt.OverflowWrap))
+	_, err := w.Write(users)
+	require.NoError(t, err)
+	require.Equal(t, "name  bio          \nAlex… likes long   \n      walks on the \n      beach        \nJo    -", b.String())
+
+	b.Reset()
+	w.TableStyle = gfmt.StyleLight
+	_, err = w.Write(users)
+	require.NoError(t, err)
+	require.Equal(t, `┌───────┬──────────────┐
+│ name  │ bio          │
+├───────┼──────────────┤
+│ Alex… │ likes long   │
+│       │ walks on the │
+│       │ beach        │
+│ Jo    │ -            │
+└───────┴──────────────┘`, b.String())
+
+	b.Reset()
+	_, err = gfmt.NewTab(b, gfmt.WithMaxWidth("Value", 4, gfmt.OverflowTruncate)).Write(newOrderedMap("key", "value"))
+	require.NoError(t, err)
+	require.Equal(t, "key val… \n", b.String())
+}
+
+func TestTab_WriteSchema(t *testing.T) {
+	ms := []any{newOrderedMap("id", 1), newOrderedMap("name", "x", "id", 2)}
+
+	b := &strings.Builder{}
+	_, err := gfmt.NewTab(b, gfmt.WithMissing[gfmt.Tab]("-")).Write(ms)
+	require.NoError(t, err)
+	require.Equal(t, "id  name \n1   -    \n2   x", b.String())
+
+	b.Reset()
+	_, err = gfmt.NewTab(b, gfmt.WithMissing[gfmt.Tab]("-"), gfmt.WithTableStyle(gfmt.StyleCompact)).Write(ms)
+	require.NoError(t, err)
+	require.Equal(t, "id  name\n--  ----\n1   -\n2   x", b.String())
+}
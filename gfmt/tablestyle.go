@@ -0,0 +1,197 @@
+// Copyright 2026 The gutenfmt authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gfmt
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/abc-inc/gutenfmt/internal/width"
+)
+
+// BorderLine defines the characters of a single line in a table.
+//
+// For horizontal lines, Fill is repeated to the width of each column.
+// For rows, the cells take the place of Fill.
+type BorderLine struct {
+	Left, Fill, Cross, Right string
+}
+
+// TableStyle defines how a Tab draws borders and separators.
+type TableStyle struct {
+	// Name is the unique name of the style e.g., "ascii".
+	Name string
+	// Top is drawn above the first row, if Top.Fill is not empty.
+	Top BorderLine
+	// Header is drawn below the header row, if Header.Fill is not empty.
+	Header BorderLine
+	// Bottom is drawn below the last row, if Bottom.Fill is not empty.
+	Bottom BorderLine
+	// Row separates the cells of a row.
+	Row BorderLine
+	// Padding is the number of spaces on either side of a cell.
+	Padding int
+
+	// plain indicates that cells are aligned like text/tabwriter does.
+	plain bool
+	// paint colors borders and separators, if not nil.
+	paint func(string) string
+}
+
+var (
+	// StylePlain aligns columns with spaces, but has neither borders nor header separator.
+	StylePlain = TableStyle{Name: "plain", plain: true}
+	// StyleASCII draws borders with ASCII characters e.g., +---+.
+	StyleASCII = TableStyle{Name: "ascii",
+		Top:     BorderLine{"+", "-", "+", "+"},
+		Header:  BorderLine{"+", "-", "+", "+"},
+		Bottom:  BorderLine{"+", "-", "+", "+"},
+		Row:     BorderLine{"|", "", "|", "|"},
+		Padding: 1,
+	}
+	// StyleLight draws borders with light Unicode box-drawing characters.
+	StyleLight = TableStyle{Name: "light",
+		Top:     BorderLine{"┌", "─", "┬", "┐"},
+		Header:  BorderLine{"├", "─", "┼", "┤"},
+		Bottom:  BorderLine{"└", "─", "┴", "┘"},
+		Row:     BorderLine{"│", "", "│", "│"},
+		Padding: 1,
+	}
+	// StyleHeavy draws borders with heavy Unicode box-drawing characters.
+	StyleHeavy = TableStyle{Name: "heavy",
+		Top:     BorderLine{"┏", "━", "┳", "┓"},
+		Header:  BorderLine{"┣", "━", "╋", "┫"},
+		Bottom:  BorderLine{"┗", "━", "┻", "┛"},
+		Row:     BorderLine{"┃", "", "┃", "┃"},
+		Padding: 1,
+	}
+	// StyleRounded draws borders with light Unicode box-drawing characters and rounded corners.
+	StyleRounded = TableStyle{Name: "rounded",
+		Top:     BorderLine{"╭", "─", "┬", "╮"},
+		Header:  BorderLine{"├", "─", "┼", "┤"},
+		Bottom:  BorderLine{"╰", "─", "┴", "╯"},
+		Row:     BorderLine{"│", "", "│", "│"},
+		Padding: 1,
+	}
+	// StyleCompact separates columns by two spaces and underlines the header.
+	StyleCompact = TableStyle{Name: "compact",
+		Header: BorderLine{"", "-", "  ", ""},
+		Row:    BorderLine{"", "", "  ", ""},
+	}
+)
+
+// tableStyles holds the predefined styles by name.
+var tableStyles = map[string]TableStyle{}
+
+func init() {
+	for _, s := range []TableStyle{StylePlain, StyleASCII, StyleLight, StyleHeavy, StyleRounded, StyleCompact} {
+		tableStyles[s.Name] = s
+	}
+}
+
+// LookupTableStyle returns the predefined TableStyle with the given name (case-insensitive).
+func LookupTableStyle(name string) (TableStyle, bool) {
+	s, ok := tableStyles[strings.ToLower(name)]
+	return s, ok
+}
+
+// TableStyleNames returns the names of all predefined table styles in sorted order.
+func TableStyleNames() []string {
+	ns := make([]string, 0, len(tableStyles))
+	for n := range tableStyles {
+		ns = append(ns, n)
+	}
+	sort.Strings(ns)
+	return ns
+}
+
+// HeaderLines returns the number of lines above the first row of a table with header.
+func (s TableStyle) HeaderLines() int {
+	n := 1
+	if s.Top.Fill != "" {
+		n++
+	}
+	if s.Header.Fill != "" {
+		n++
+	}
+	return n
+}
+
+// border colors borders and separators, if requested.
+// Whitespace is never colored, so that it can be trimmed.
+func (s TableStyle) border(text string) string {
+	if s.paint == nil || strings.TrimSpace(text) == "" {
+		return text
+	}
+	return s.paint(text)
+}
+
+// line draws a horizontal line for columns of the given widths.
+func (s TableStyle) line(b *strings.Builder, l BorderLine, ws []int) {
+	h := &strings.Builder{}
+	h.WriteString(l.Left)
+	for idx, w := range ws {
+		if idx > 0 {
+			h.WriteString(l.Cross)
+		}
+		h.WriteString(strings.Repeat(l.Fill, w+2*s.Padding))
+	}
+	h.WriteString(l.Right)
+	b.WriteString(s.border(strings.TrimRight(h.String(), " ")))
+	b.WriteString("\n")
+}
+
+// row draws the cells of a row, each padded to the width of its column.
+// Cells spanning multiple lines are drawn below each other.
+func (s TableStyle) row(b *strings.Builder, cs [][]string, ws []int) {
+	n := 0
+	for _, c := range cs {
+		n = max(n, len(c))
+	}
+	l := make([]string, len(cs))
+	for ln := 0; ln < n; ln++ {
+		for idx, c := range cs {
+			l[idx] = ""
+			if ln < len(c) {
+				l[idx] = c[ln]
+			}
+		}
+		s.rowLine(b, l, ws)
+	}
+}
+
+// rowLine draws a single line of a row.
+// Trailing whitespace is removed, if the row has no right border.
+func (s TableStyle) rowLine(b *strings.Builder, cs []string, ws []int) {
+	l := &strings.Builder{}
+	l.WriteString(s.border(s.Row.Left))
+	pad := strings.Repeat(" ", s.Padding)
+	for idx, c := range cs {
+		if idx > 0 {
+			l.WriteString(s.border(s.Row.Cross))
+		}
+		l.WriteString(pad)
+		l.WriteString(c)
+		l.WriteString(strings.Repeat(" ", ws[idx]-width.String(c)))
+		l.WriteString(pad)
+	}
+	l.WriteString(s.border(s.Row.Right))
+	if s.Row.Right == "" {
+		b.WriteString(strings.TrimRight(l.String(), " "))
+	} else {
+		b.WriteString(l.String())
+	}
+	b.WriteString("\n")
+}
@@ -0,0 +1,44 @@
+// Copyright 2026 The gutenfmt authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gfmt
+
+import (
+	"os"
+	"strconv"
+)
+
+// TerminalWidth returns the number of columns of the terminal referred to by fd.
+//
+// The environment variable COLUMNS takes precedence, if it holds a positive number.
+// It returns 0, if the width cannot be determined e.g., because fd is not a terminal.
+func TerminalWidth(fd uintptr) int {
+	if n, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && n > 0 {
+		return n
+	}
+	w, _ := termSize(fd)
+	return w
+}
+
+// TerminalHeight returns the number of lines of the terminal referred to by fd.
+//
+// The environment variable LINES takes precedence, if it holds a positive number.
+// It returns 0, if the height cannot be determined e.g., because fd is not a terminal.
+func TerminalHeight(fd uintptr) int {
+	if n, err := strconv.Atoi(os.Getenv("LINES")); err == nil && n > 0 {
+		return n
+	}
+	_, h := termSize(fd)
+	return h
+}
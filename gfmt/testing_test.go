@@ -17,6 +17,8 @@ package gfmt_test
 import (
 	"math"
 	"strings"
+
+	"github.com/abc-inc/gutenfmt/ordered"
 )
 
 type User struct {
@@ -164,3 +166,12 @@ var jsonTypes = JSONTypes{
 	*NewUser("f", "l"),
 	[]User{*NewUser("af", "al"), *NewUser("bf", "bl")},
 }
+
+// newOrderedMap creates an ordered.Map from alternating keys and values.
+func newOrderedMap(kvs ...any) *ordered.Map {
+	m := ordered.NewMap(len(kvs) / 2)
+	for idx := 0; idx+1 < len(kvs); idx += 2 {
+		m.Set(kvs[idx].(string), kvs[idx+1])
+	}
+	return m
+}
@@ -19,8 +19,11 @@ import (
 	"fmt"
 	"io"
 	"reflect"
+	"strings"
 
 	"github.com/abc-inc/gutenfmt/formatter"
+	"github.com/abc-inc/gutenfmt/internal/render"
+	"github.com/alecthomas/chroma/v2"
 )
 
 // Text is a generic Writer that formats arbitrary values as plain text.
@@ -29,11 +32,23 @@ type Text struct {
 	Formatter *formatter.CompFormatter
 	Sep       string
 	Delim     string
+	// SortKeys sorts the keys of maps, which do not define an order.
+	SortKeys bool
+	// Flatten expands nested values of records into separate columns, if not nil.
+	Flatten *Flatten
+	// Missing is the placeholder for cells, whose record does not have the column's key.
+	Missing string
+	// Style colors headers, names, separators and values, nil disables colors.
+	Style *chroma.Style
 }
 
 // NewText creates a new text Writer.
-func NewText(w io.Writer) *Text {
-	return &Text{w, formatter.NewComp(), ":", "\n"}
+func NewText(w io.Writer, opts ...Opt[Text]) *Text {
+	gw := &Text{writer: w, Formatter: formatter.NewComp(), Sep: ":", Delim: "\n"}
+	for _, opt := range opts {
+		opt(gw)
+	}
+	return gw
 }
 
 // Write writes the text representation of the given value to the underlying Writer.
@@ -48,8 +63,24 @@ func (w Text) Write(i any) (int, error) {
 		return 0, err
 	}
 
+	if w.Flatten != nil {
+		c := w
+		c.Flatten = nil
+		return c.Write(w.Flatten.flatten(i, w.SortKeys))
+	}
+
+	if p := newPainter(w.Style, w.SortKeys); p != nil {
+		p.stringers = true
+		p.render = w.render
+		c := w
+		c.Style, c.Sep = nil, p.punctuation(w.Sep)
+		return c.Write(p.colorize(i))
+	}
+
 	typ := reflect.TypeOf(i)
-	if typ.Kind() == reflect.Ptr {
+	if formatter.IsMap(i) {
+		return w.writeMap(i)
+	} else if typ.Kind() == reflect.Ptr {
 		return w.Write(reflect.Indirect(reflect.ValueOf(i)).Interface())
 	} else if str, ok := i.(fmt.Stringer); ok {
 		return fmt.Fprint(w.writer, str.String())
@@ -60,8 +91,6 @@ func (w Text) Write(i any) (int, error) {
 	switch typ.Kind() { //nolint:exhaustive
 	case reflect.Slice, reflect.Array:
 		return w.writeSlice(reflect.ValueOf(i))
-	case reflect.Map:
-		return w.writeMap(reflect.ValueOf(i).MapRange())
 	case reflect.Struct:
 		return w.writeStruct(reflect.ValueOf(i))
 	default:
@@ -69,6 +98,16 @@ func (w Text) Write(i any) (int, error) {
 	}
 }
 
+// render returns the text representation of i without colors.
+func (w Text) render(i any) string {
+	b := &strings.Builder{}
+	w.writer, w.Style = b, nil
+	if _, err := w.Write(i); err != nil {
+		return render.ToString(i)
+	}
+	return b.String()
+}
+
 // writeSlice writes the text representation of the given slice to the underlying Writer.
 func (w Text) writeSlice(v reflect.Value) (int, error) {
 	if v.Type().Elem().Kind() == reflect.Struct ||
@@ -79,7 +118,7 @@ func (w Text) writeSlice(v reflect.Value) (int, error) {
 	if v.Len() == 0 {
 		return 0, nil
 	}
-	if typ := reflect.TypeOf(reflect.Indirect(v.Index(0)).Interface()); typ != nil && typ.Kind() == reflect.Map {
+	if formatter.IsMap(v.Index(0).Interface()) {
 		return w.writeMapSlice(v.Interface())
 	}
 
@@ -106,37 +145,34 @@ func (w Text) writeSlice(v reflect.Value) (int, error) {
 }
 
 // writeMap writes the text representation of the given map to the underlying Writer.
-func (w Text) writeMap(iter *reflect.MapIter) (int, error) {
-	if !iter.Next() {
-		return 0, nil
-	}
-
-	n, err := w.writeKeyVal(iter.Key().Interface(), iter.Value().Interface())
-	if err != nil {
-		return n, err
-	}
-	cnt := n
-
-	for iter.Next() {
-		n, err = io.WriteString(w.writer, w.Delim)
-		cnt += n
-		if err != nil {
-			return cnt, err
+// The entries of an ordered.Map are written in order.
+func (w Text) writeMap(i any) (int, error) {
+	cnt := 0
+	for idx, k := range formatter.MapKeys(i, w.SortKeys) {
+		if idx > 0 {
+			n, err := io.WriteString(w.writer, w.Delim)
+			cnt += n
+			if err != nil {
+				return cnt, err
+			}
 		}
 
-		n, err = w.writeKeyVal(iter.Key().Interface(), iter.Value().Interface())
+		var v any
+		if mv := formatter.MapIndex(i, k); mv.IsValid() {
+			v = mv.Interface()
+		}
+		n, err := w.writeKeyVal(k.Interface(), v)
 		cnt += n
 		if err != nil {
 			return cnt, err
 		}
 	}
-
 	return cnt, nil
 }
 
 func (w Text) writeMapSlice(i any) (int, error) {
-	f := formatter.FromMapSlice(w.Sep, w.Delim)
-	s, err := f.Format(i)
+	ks := formatter.MapSliceKeys(i, w.SortKeys)
+	s, err := formatter.FromMapSliceKeysMissing(w.Sep, w.Delim, w.Missing, ks...).Format(i)
 	if err != nil {
 		return 0, err
 	}
@@ -106,3 +106,39 @@ func TestText_WriteMapSlice(t *testing.T) {
 	require.NoError(t, err)
 	require.Regexp(t, "^(a:b\n97:true\nc:d)|(b:a\ntrue:97\nd:c)", b.String())
 }
+
+func TestText_WriteOrderedMap(t *testing.T) {
+	b := &strings.Builder{}
+	w := gfmt.NewText(b)
+	w.Sep = "="
+	_, err := w.Write(newOrderedMap("z", 1, "a", nil, "m", newOrderedMap("y", 2, "b", 3)))
+	require.NoError(t, err)
+	require.Equal(t, "z=1\na=\nm=y=2\nb=3", b.String())
+
+	b.Reset()
+	_, err = w.Write([]any{newOrderedMap("z", 1, "a", 2), newOrderedMap("a", 3, "z", 4)})
+	require.NoError(t, err)
+	require.Equal(t, "z=a\n1=2\n4=3", b.String())
+}
+
+func TestText_WriteSortKeys(t *testing.T) {
+	b := &strings.Builder{}
+	w := gfmt.NewText(b, gfmt.WithSortKeys[gfmt.Text]())
+	_, err := w.Write(map[string]any{"c": 1, "a": 2, "b": 3})
+	require.NoError(t, err)
+	require.Equal(t, "a:2\nb:3\nc:1", b.String())
+
+	b.Reset()
+	_, err = w.Write([]map[string]int{{"c": 1, "a": 2}, {"a": 3, "c": 4}})
+	require.NoError(t, err)
+	require.Equal(t, "a:c\n2:1\n3:4", b.String())
+}
+
+func TestText_WriteSchema(t *testing.T) {
+	b := &strings.Builder{}
+	w := gfmt.NewText(b, gfmt.WithMissing[gfmt.Text]("?"), gfmt.WithSortKeys[gfmt.Text]())
+	w.Sep = ","
+	_, err := w.Write([]map[string]int{{"b": 1}, {"a": 2}})
+	require.NoError(t, err)
+	require.Equal(t, "a,b\n?,1\n2,?", b.String())
+}
@@ -14,7 +14,13 @@
 
 package gfmt
 
-import "reflect"
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/abc-inc/gutenfmt/internal/render"
+	"github.com/abc-inc/gutenfmt/internal/table"
+)
 
 // isContainerType returns true if a type is kind of a "container".
 //
@@ -26,3 +32,18 @@ func isContainerType(k reflect.Kind) bool {
 	return k == reflect.Struct || k == reflect.Slice ||
 		k == reflect.Map || k == reflect.Array
 }
+
+// cellString returns the string representation of a table cell.
+// Nested values i.e., maps, structs, slices and arrays except byte slices, are encoded as JSON.
+func cellString(c any) (string, error) {
+	if c == nil {
+		return "", nil
+	}
+	if k := reflect.Indirect(reflect.ValueOf(c)).Kind(); table.IsRecord(c) ||
+		(k == reflect.Slice || k == reflect.Array) && reflect.TypeOf(c).Elem().Kind() != reflect.Uint8 {
+
+		b, err := json.Marshal(c)
+		return string(b), err
+	}
+	return render.ToString(c), nil
+}
@@ -0,0 +1,135 @@
+// Copyright 2026 The gutenfmt authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gfmt
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/abc-inc/gutenfmt/internal/render"
+	"github.com/abc-inc/gutenfmt/internal/table"
+	"github.com/abc-inc/gutenfmt/internal/width"
+)
+
+// Vertical determines whether a Tab prints records as blocks of name and value lines.
+type Vertical int
+
+const (
+	// VerticalOff prints one row per record.
+	VerticalOff Vertical = iota
+	// VerticalOn prints one block per record.
+	VerticalOn
+	// VerticalAuto prints one block per record, if the table is wider than Tab.Width.
+	VerticalAuto
+)
+
+// ParseVertical converts "off", "on" or "auto" to the respective Vertical mode.
+func ParseVertical(s string) (Vertical, error) {
+	switch strings.ToLower(s) {
+	case "off", "false", "":
+		return VerticalOff, nil
+	case "on", "true":
+		return VerticalOn, nil
+	case "auto":
+		return VerticalAuto, nil
+	}
+	return VerticalOff, fmt.Errorf("invalid vertical mode: %q", s)
+}
+
+// vertical returns the Table of records to be printed vertically or nil.
+func (w Tab) vertical(i any) *table.Table {
+	if w.Vertical == VerticalOff || (w.Vertical == VerticalAuto && w.Width <= 0) {
+		return nil
+	}
+	t, ok := table.From(i, table.Options{SortKeys: w.SortKeys, Missing: w.Missing})
+	if !ok || t.Header == nil || len(t.Rows) == 0 {
+		return nil
+	}
+	if w.Vertical == VerticalAuto {
+		b := &strings.Builder{}
+		c := w
+		c.cw, c.Vertical = wrapCountingWriter(b), VerticalOff
+		if _, err := c.Write(i); err != nil {
+			return nil
+		}
+		for _, l := range strings.Split(b.String(), "\n") {
+			if width.String(l) > w.Width {
+				return t
+			}
+		}
+		return nil
+	}
+	return t
+}
+
+// writeVertical prints each record as a block of aligned name and value lines,
+// preceded by a line like "-[ RECORD 1 ]-".
+func (w Tab) writeVertical(t *table.Table) (int, error) {
+	nw := 0
+	for _, n := range t.Header {
+		nw = max(nw, width.String(n))
+	}
+
+	sep := w.TableStyle.border(" | ")
+	recs := make([][]string, len(t.Rows))
+	vw := 0
+	for r, row := range t.Rows {
+		for idx, n := range t.Header {
+			var ls []string
+			for _, l := range strings.Split(render.ToString(row[idx]), "\n") {
+				ls = append(ls, w.shorten(n, l)...)
+			}
+			for ln, l := range ls {
+				vw = max(vw, width.String(l))
+				if ln == 0 {
+					l = n + strings.Repeat(" ", nw-width.String(n)) + sep + l
+				} else {
+					l = strings.Repeat(" ", nw) + sep + l
+				}
+				recs[r] = append(recs[r], strings.TrimRight(l, " "))
+			}
+		}
+	}
+
+	ruler := strings.Repeat("-", nw+1) + "+" + strings.Repeat("-", vw+1)
+	b := &strings.Builder{}
+	for r, ls := range recs {
+		if r > 0 {
+			b.WriteString("\n")
+		}
+		lbl := fmt.Sprintf("-[ RECORD %d ]", r+1)
+		if len(lbl) < len(ruler) {
+			lbl += ruler[len(lbl):]
+		} else {
+			lbl += "-"
+		}
+		b.WriteString(w.TableStyle.border(lbl) + "\n" + strings.Join(ls, "\n"))
+	}
+	return w.cw.WriteString(b.String())
+}
+
+// WithVertical sets the Vertical mode of a Tab Writer.
+func WithVertical(v Vertical) Opt[Tab] {
+	return func(w *Tab) {
+		w.Vertical = v
+	}
+}
+
+// WithWidth sets the available width of a Tab Writer, which is used by VerticalAuto.
+func WithWidth(n int) Opt[Tab] {
+	return func(w *Tab) {
+		w.Width = n
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright 2026 The gutenfmt authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gfmt_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/abc-inc/gutenfmt/gfmt"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseVertical(t *testing.T) {
+	for s, want := range map[string]gfmt.Vertical{"off": gfmt.VerticalOff, "ON": gfmt.VerticalOn, "auto": gfmt.VerticalAuto} {
+		v, err := gfmt.ParseVertical(s)
+		require.NoError(t, err)
+		require.Equal(t, want, v)
+	}
+	_, err := gfmt.ParseVertical("sideways")
+	require.Error(t, err)
+}
+
+func TestTab_WriteVertical(t *testing.T) {
+	b := &strings.Builder{}
+	_, err := gfmt.NewTab(b, gfmt.WithVertical(gfmt.VerticalOn)).Write([]*User{NewUser("John", "Doe"), NewUser("Li", "Na")})
+	require.NoError(t, err)
+	require.Equal(t, `-[ RECORD 1 ]------------
+username | John Doe
+email    | john.doe@local
+-[ RECORD 2 ]------------
+username | Li Na
+email    | li.na@local`, b.String())
+
+	b.Reset()
+	w := gfmt.NewTab(b, gfmt.WithVertical(gfmt.VerticalOn), gfmt.WithMaxWidth("bio", 6, gfmt.OverflowWrap))
+	_, err = w.Write([]any{newOrderedMap("id", 1, "bio", "very long text")})
+	require.NoError(t, err)
+	require.Equal(t, "-[ RECORD 1 ]-\nid  | 1\nbio | very\n    | long\n    | text", b.String())
+
+	b.Reset()
+	_, err = w.Write(newOrderedMap("id", 1))
+	require.NoError(t, err)
+	require.Equal(t, "id  1   \n", b.String())
+}
+
+func TestTab_WriteVerticalAuto(t *testing.T) {
+	users := []*User{NewUser("John", "Doe")}
+
+	b := &strings.Builder{}
+	_, err := gfmt.NewTab(b, gfmt.WithVertical(gfmt.VerticalAuto), gfmt.WithWidth(30)).Write(users)
+	require.NoError(t, err)
+	require.Equal(t, "username email \nJohn Doe john.doe@local", b.String())
+
+	b.Reset()
+	_, err = gfmt.NewTab(b, gfmt.WithVertical(gfmt.VerticalAuto), gfmt.WithWidth(20)).Write(users)
+	require.NoError(t, err)
+	require.Equal(t, "-[ RECORD 1 ]------------\nusername | John Doe\nemail    | john.doe@local", b.String())
+
+	b.Reset()
+	_, err = gfmt.NewTab(b, gfmt.WithVertical(gfmt.VerticalAuto)).Write(users)
+	require.NoError(t, err)
+	require.Equal(t, "username email \nJohn Doe john.doe@local", b.String())
+}
@@ -127,8 +127,61 @@ func WithStyle[W Writer](s *chroma.Style) Opt[W] {
 		switch reflect.TypeOf(w) {
 		case reflect.TypeOf(&JSON{}):
 			any(w).(*JSON).Style = s
+		case reflect.TypeOf(&Tab{}):
+			any(w).(*Tab).Style = s
+		case reflect.TypeOf(&Text{}):
+			any(w).(*Text).Style = s
 		case reflect.TypeOf(&YAML{}):
 			any(w).(*YAML).Style = s
 		}
 	}
 }
+
+// WithSortKeys sorts the keys of maps, which do not define an order, for the given Writer.
+// JSON and YAML always sort the keys of regular maps.
+func WithSortKeys[W Writer]() Opt[W] {
+	return func(w *W) {
+		switch reflect.TypeOf(w) {
+		case reflect.TypeOf(&CSV{}):
+			any(w).(*CSV).SortKeys = true
+		case reflect.TypeOf(&HTML{}):
+			any(w).(*HTML).SortKeys = true
+		case reflect.TypeOf(&JQ{}):
+			any(w).(*JQ).SortKeys = true
+		case reflect.TypeOf(&Markdown{}):
+			any(w).(*Markdown).SortKeys = true
+		case reflect.TypeOf(&Tab{}):
+			any(w).(*Tab).SortKeys = true
+		case reflect.TypeOf(&Text{}):
+			any(w).(*Text).SortKeys = true
+		}
+	}
+}
+
+// WithFlatten expands nested values of records into columns named by their path, for the given Writer.
+func WithFlatten[W Writer](f Flatten) Opt[W] {
+	return func(w *W) {
+		switch reflect.TypeOf(w) {
+		case reflect.TypeOf(&CSV{}):
+			any(w).(*CSV).Flatten = &f
+		case reflect.TypeOf(&Tab{}):
+			any(w).(*Tab).Flatten = &f
+		case reflect.TypeOf(&Text{}):
+			any(w).(*Text).Flatten = &f
+		}
+	}
+}
+
+// WithMissing sets the placeholder for cells, whose record does not have the column's key, for the given Writer.
+func WithMissing[W Writer](placeholder string) Opt[W] {
+	return func(w *W) {
+		switch reflect.TypeOf(w) {
+		case reflect.TypeOf(&CSV{}):
+			any(w).(*CSV).Missing = placeholder
+		case reflect.TypeOf(&Tab{}):
+			any(w).(*Tab).Missing = placeholder
+		case reflect.TypeOf(&Text{}):
+			any(w).(*Text).Missing = placeholder
+		}
+	}
+}
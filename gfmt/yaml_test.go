@@ -64,3 +64,10 @@ func TestYAML_WriteStruct(t *testing.T) {
 	require.Equal(t, 41, n)
 	require.Equal(t, b.String(), "Username: John Doe\nE-Mail: john.doe@local")
 }
+
+func TestYAML_WriteOrderedMap(t *testing.T) {
+	b := &strings.Builder{}
+	_, err := gfmt.NewYAML(b).Write([]any{newOrderedMap("z", 1, "a", newOrderedMap("x", 2, "b", 3))})
+	require.NoError(t, err)
+	require.Equal(t, "- z: 1\n  a:\n    x: 2\n    b: 3", b.String())
+}
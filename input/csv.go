@@ -0,0 +1,140 @@
+// Copyright 2026 The gutenfmt authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package input
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/abc-inc/gutenfmt/ordered"
+)
+
+// CSV decodes delimiter-separated values as specified in RFC 4180.
+//
+// Each row is converted to an *ordered.Map, whose keys are taken from the
+// header row.
+// Rows with fewer fields than the header lack the respective keys, whereas
+// additional fields are named as if there was no header.
+type CSV struct {
+	// Comma is the field delimiter e.g., ',' or '\t'.
+	Comma rune
+	// NoHeader indicates that the first row contains data.
+	// The columns are named column1, column2, etc. instead.
+	NoHeader bool
+	// InferTypes converts empty fields to nil and fields that look like
+	// booleans or numbers to bool, int and float64, respectively.
+	InferTypes bool
+}
+
+var _ Decoder = (*CSV)(nil)
+
+const (
+	// CSVSniffFields is the minimum number of fields per row, for which the input is detected as CSV.
+	CSVSniffFields = 2
+	// TSVSniffFields is the minimum number of fields per row, for which the input is detected as TSV.
+	TSVSniffFields = 3
+)
+
+// Decode reads all records from r and returns them as slice of *ordered.Map.
+func (d CSV) Decode(r io.Reader) (any, error) {
+	cr := d.newReader(r)
+	var hdr []string
+	if !d.NoHeader {
+		rec, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return []any{}, nil
+		} else if err != nil {
+			return nil, err
+		}
+		hdr = append(hdr, rec...)
+	}
+
+	rows := []any{}
+	for {
+		rec, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		m := ordered.NewMap(len(rec))
+		for idx, f := range rec {
+			var v any = f
+			if d.InferTypes {
+				v = inferType(f)
+			}
+			m.Set(columnName(hdr, idx), v)
+		}
+		rows = append(rows, m)
+	}
+	return rows, nil
+}
+
+// Sniff reports whether b looks like a table with the configured delimiter,
+// i.e., at least two rows with the same number of fields, which is at least min.
+func (d CSV) Sniff(minFields int) func(b []byte) bool {
+	return func(b []byte) bool {
+		cr := d.newReader(bytes.NewReader(b))
+		cr.FieldsPerRecord = 0
+		rows := 0
+		for ; rows < 10; rows++ {
+			rec, err := cr.Read()
+			if errors.Is(err, io.EOF) {
+				break
+			} else if err != nil || len(rec) < minFields {
+				return false
+			}
+		}
+		return rows > 1
+	}
+}
+
+func (d CSV) newReader(r io.Reader) *csv.Reader {
+	cr := csv.NewReader(r)
+	cr.Comma = d.Comma
+	cr.FieldsPerRecord = -1
+	cr.ReuseRecord = true
+	return cr
+}
+
+// columnName returns the header name at the given index, if any.
+// Otherwise, a name is generated from the 1-based column number.
+func columnName(hdr []string, idx int) string {
+	if idx < len(hdr) {
+		return hdr[idx]
+	}
+	return "column" + strconv.Itoa(idx+1)
+}
+
+// inferType converts a field to the most specific type.
+func inferType(s string) any {
+	t := strings.TrimSpace(s)
+	if t == "" {
+		return nil
+	} else if t == "true" || t == "false" {
+		return t == "true"
+	} else if i, err := strconv.ParseInt(t, 10, 0); err == nil {
+		return int(i)
+	} else if f, err := strconv.ParseFloat(t, 64); err == nil && !math.IsInf(f, 0) && !math.IsNaN(f) {
+		return f
+	}
+	return s
+}
@@ -0,0 +1,68 @@
+// Copyright 2026 The gutenfmt authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package input_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/abc-inc/gutenfmt/input"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCSV_Decode(t *testing.T) {
+	in := "name,age,note\nJane,42,\"Doe, \"\"J\"\"\"\nJohn,7.5\n\"multi\nline\",true,,x\n"
+	v, err := input.CSV{Comma: ','}.Decode(strings.NewReader(in))
+	require.NoError(t, err)
+	requireJSON(t, `[{"name":"Jane","age":"42","note":"Doe, \"J\""},`+
+		`{"name":"John","age":"7.5"},`+
+		`{"name":"multi\nline","age":"true","note":"","column4":"x"}]`, v)
+
+	v, err = input.CSV{Comma: ',', InferTypes: true}.Decode(strings.NewReader(in))
+	require.NoError(t, err)
+	requireJSON(t, `{"name":"John","age":7.5}`, v.([]any)[1])
+	requireJSON(t, `{"name":"multi\nline","age":true,"note":null,"column4":"x"}`, v.([]any)[2])
+}
+
+func TestCSV_DecodeNoHeader(t *testing.T) {
+	v, err := input.CSV{Comma: ';', NoHeader: true, InferTypes: true}.Decode(strings.NewReader("a;1\nb;-2\n"))
+	require.NoError(t, err)
+	requireJSON(t, `[{"column1":"a","column2":1},{"column1":"b","column2":-2}]`, v)
+}
+
+func TestCSV_DecodeError(t *testing.T) {
+	_, err := input.CSV{Comma: ','}.Decode(strings.NewReader("a,b\n\"x,y\n"))
+	require.Error(t, err)
+
+	v, err := input.CSV{Comma: ','}.Decode(strings.NewReader(""))
+	require.NoError(t, err)
+	require.Empty(t, v)
+}
+
+func TestCSV_Sniff(t *testing.T) {
+	sniff := input.CSV{Comma: ','}.Sniff(2)
+	require.True(t, sniff([]byte("a,b\n1,2\n")))
+	require.False(t, sniff([]byte("a,b\n")))
+	require.False(t, sniff([]byte("a,b\n1,2,3\n")))
+	require.False(t, sniff([]byte("a=1\nb=2\n")))
+
+	v, err := input.Decode(strings.NewReader("a\tb\n1\t2\n"), input.Auto)
+	require.NoError(t, err)
+	requireJSON(t, `{"a":"b","1":"2"}`, v)
+
+	v, err = input.Decode(strings.NewReader("a\tb\tc\n1\t2\t3\n"), input.Auto)
+	require.NoError(t, err)
+	requireJSON(t, `[{"a":"1","b":"2","c":"3"}]`, v)
+}
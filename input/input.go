@@ -0,0 +1,183 @@
+// Copyright 2026 The gutenfmt authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package input provides decoders, which convert raw input such as JSON or
+// name and value pairs to generic values that can be passed to a gfmt.Writer.
+package input
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Auto is the name of the pseudo format, which detects the input format by content.
+const Auto = "auto"
+
+// ErrUnknownFormat is the error resulting if no Decoder is registered for a name.
+var ErrUnknownFormat = errors.New("unknown input format")
+
+// Stream holds the documents of a multi-document input e.g., JSON Lines, in order.
+type Stream []any
+
+// Decoder is the interface that wraps the Decode method.
+type Decoder interface {
+	// Decode reads the whole input from r and returns its value.
+	Decode(r io.Reader) (any, error)
+}
+
+// Func is an adapter to allow the use of ordinary functions as Decoders.
+// If f is a function with the appropriate signature,
+// Func(f) is a Decoder that calls f.
+type Func func(r io.Reader) (any, error)
+
+// Decode returns the value by applying f to r.
+func (f Func) Decode(r io.Reader) (any, error) {
+	return f(r)
+}
+
+// Format describes a named input format.
+type Format struct {
+	// Name is the unique name of the format e.g., "json".
+	Name string
+	// Description is a short, human-readable description of the format.
+	Description string
+	// Decoder converts the input to a generic value.
+	Decoder Decoder
+	// Sniff reports whether the content looks like the format.
+	// Formats without Sniff function are never chosen by auto-detection.
+	Sniff func(b []byte) bool
+}
+
+// Registry holds named input formats.
+//
+// The order of registration determines the precedence during auto-detection.
+type Registry struct {
+	formats []Format
+}
+
+// NewRegistry creates a new Registry without any formats.
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Register adds the Format to the Registry.
+// If a Format with the same name already exists, it is replaced in place.
+func (r *Registry) Register(f Format) {
+	for idx := range r.formats {
+		if r.formats[idx].Name == f.Name {
+			r.formats[idx] = f
+			return
+		}
+	}
+	r.formats = append(r.formats, f)
+}
+
+// Lookup returns the Format registered under the given name (case-insensitive).
+func (r *Registry) Lookup(name string) (Format, bool) {
+	for _, f := range r.formats {
+		if strings.EqualFold(f.Name, name) {
+			return f, true
+		}
+	}
+	return Format{}, false
+}
+
+// Formats returns all registered formats in order of registration.
+func (r *Registry) Formats() []Format {
+	return append([]Format(nil), r.formats...)
+}
+
+// Names returns the names of all registered formats in order of registration.
+func (r *Registry) Names() []string {
+	ns := make([]string, len(r.formats))
+	for idx, f := range r.formats {
+		ns[idx] = f.Name
+	}
+	return ns
+}
+
+// Detect returns the names of all formats whose Sniff function accepts b,
+// in order of precedence.
+func (r *Registry) Detect(b []byte) (ns []string) {
+	for _, f := range r.formats {
+		if f.Sniff != nil && f.Sniff(b) {
+			ns = append(ns, f.Name)
+		}
+	}
+	return
+}
+
+// Decode reads the whole input from rd and decodes it using the named format.
+//
+// If name is empty or Auto, the candidates returned by Detect are tried in
+// order and the result of the first one, which does not fail, is returned.
+func (r *Registry) Decode(rd io.Reader, name string) (any, error) {
+	if name != "" && !strings.EqualFold(name, Auto) {
+		f, ok := r.Lookup(name)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownFormat, name)
+		}
+		return f.Decoder.Decode(rd)
+	}
+
+	b, err := io.ReadAll(rd)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ErrUnknownFormat
+	for _, n := range r.Detect(b) {
+		f, _ := r.Lookup(n)
+		var v any
+		if v, err = f.Decoder.Decode(bytes.NewReader(b)); err == nil {
+			return v, nil
+		}
+	}
+	return nil, err
+}
+
+// Default is the Registry used by the package-level functions.
+var Default = NewRegistry()
+
+func init() {
+	Default.Register(Format{"json", "JSON value or stream of concatenated JSON values", Func(DecodeJSON), SniffJSON})
+	Default.Register(Format{"ndjson", "Newline-delimited JSON (JSON Lines)", Func(DecodeNDJSON), nil})
+	Default.Register(Format{"csv", "Comma-separated values with header row", CSV{Comma: ','}, CSV{Comma: ','}.Sniff(CSVSniffFields)})
+	Default.Register(Format{"tsv", "Tab-separated values with header row", CSV{Comma: '\t'}, CSV{Comma: '\t'}.Sniff(TSVSniffFields)})
+	Default.Register(Format{"yaml", "YAML document or stream of documents separated by ---", Func(DecodeYAML), SniffYAML})
+	Default.Register(Format{"kv", "Name and value pairs, separated by equal sign, colon or tab", Func(DecodeKV), SniffKV})
+}
+
+// Register adds the Format to the Default Registry.
+func Register(f Format) {
+	Default.Register(f)
+}
+
+// Lookup returns the Format registered under the given name in the Default Registry.
+func Lookup(name string) (Format, bool) {
+	return Default.Lookup(name)
+}
+
+// Names returns the names of all formats in the Default Registry.
+func Names() []string {
+	return Default.Names()
+}
+
+// Decode reads the whole input from r and decodes it using the Default Registry.
+func Decode(r io.Reader, name string) (any, error) {
+	return Default.Decode(r, name)
+}
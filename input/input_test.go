@@ -0,0 +1,82 @@
+// Copyright 2026 The gutenfmt authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package input_test
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/abc-inc/gutenfmt/input"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		in     string
+		want   string
+	}{
+		{"auto_json", "", `{"b":1,"a":2}`, `{"b":1,"a":2}`},
+		{"auto_kv", input.Auto, "b=1\na:2\nc\t3\nd", `{"b":"1","a":"2","c":"3"}`},
+		{"auto_empty", "", "", "null"},
+		{"json", "JSON", `[1, "a"]`, `[1,"a"]`},
+		{"kv", "kv", `{"a":1}`, `{"{\"a\"":"1}"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := input.Decode(strings.NewReader(tt.in), tt.format)
+			require.NoError(t, err)
+			requireJSON(t, tt.want, v)
+		})
+	}
+}
+
+func TestDecode_error(t *testing.T) {
+	_, err := input.Decode(strings.NewReader(""), "unknown")
+	require.ErrorIs(t, err, input.ErrUnknownFormat)
+
+	_, err = input.Decode(strings.NewReader("{"), "json")
+	require.Error(t, err)
+}
+
+func TestRegistry(t *testing.T) {
+	errFail := errors.New("fail")
+	r := input.NewRegistry()
+	r.Register(input.Format{Name: "fail", Decoder: input.Func(func(io.Reader) (any, error) {
+		return nil, errFail
+	}), Sniff: func([]byte) bool { return true }})
+	r.Register(input.Format{Name: "upper", Decoder: input.Func(func(rd io.Reader) (any, error) {
+		b, err := io.ReadAll(rd)
+		return strings.ToUpper(string(b)), err
+	}), Sniff: func(b []byte) bool { return len(b) > 0 }})
+
+	require.Equal(t, []string{"fail", "upper"}, r.Names())
+	require.Equal(t, []string{"fail"}, r.Detect(nil))
+
+	v, err := r.Decode(strings.NewReader("x"), input.Auto)
+	require.NoError(t, err)
+	require.Equal(t, "X", v)
+
+	_, err = r.Decode(strings.NewReader(""), input.Auto)
+	require.ErrorIs(t, err, errFail)
+
+	r.Register(input.Format{Name: "fail", Decoder: input.Func(input.DecodeKV)})
+	require.Equal(t, []string{"fail", "upper"}, r.Names())
+	require.Equal(t, []string{"upper"}, r.Detect([]byte("x")))
+}
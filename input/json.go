@@ -0,0 +1,81 @@
+// Copyright 2026 The gutenfmt authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package input
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+
+	"github.com/abc-inc/gutenfmt/ordered"
+)
+
+// DecodeJSON decodes a JSON value or a stream of concatenated JSON values.
+//
+// A single value is returned as is, whereas multiple values are returned as
+// Stream. Whitespace, including newlines as in JSON Lines, is permitted
+// between values. Objects are decoded as *ordered.Map to preserve the order
+// of their keys.
+func DecodeJSON(r io.Reader) (any, error) {
+	s, err := decodeJSONStream(r)
+	if err != nil {
+		return nil, err
+	} else if len(s) == 1 {
+		return s[0], nil
+	}
+	return s, nil
+}
+
+// DecodeNDJSON decodes newline-delimited JSON (JSON Lines).
+// Unlike DecodeJSON, the result is always a Stream, even for a single line.
+func DecodeNDJSON(r io.Reader) (any, error) {
+	return decodeJSONStream(r)
+}
+
+// decodeJSONStream decodes all JSON values until EOF.
+// An empty input results in io.EOF.
+func decodeJSONStream(r io.Reader) (Stream, error) {
+	var s Stream
+	d := json.NewDecoder(r)
+	for {
+		v, err := ordered.DecodeJSON(d)
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		s = append(s, v)
+	}
+	if len(s) == 0 {
+		return nil, io.EOF
+	}
+	return s, nil
+}
+
+// SniffJSON reports whether b starts like a JSON value.
+func SniffJSON(b []byte) bool {
+	b = bytes.TrimLeft(b, " \t\r\n")
+	if len(b) == 0 {
+		return false
+	}
+	switch c := b[0]; {
+	case c == '{', c == '[', c == '"', c == '-', c >= '0' && c <= '9':
+		return true
+	default:
+		return bytes.HasPrefix(b, []byte("true")) || bytes.HasPrefix(b, []byte("false")) ||
+			bytes.HasPrefix(b, []byte("null"))
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright 2026 The gutenfmt authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package input_test
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/abc-inc/gutenfmt/input"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSniffJSON(t *testing.T) {
+	for _, s := range []string{"{}", " [1]", "\n\"a\"", "-1", "42", "true", "false", "null"} {
+		require.True(t, input.SniffJSON([]byte(s)), s)
+	}
+	for _, s := range []string{"", " ", "a=b", "x: y", "nil"} {
+		require.False(t, input.SniffJSON([]byte(s)), s)
+	}
+}
+
+func TestDecodeJSON(t *testing.T) {
+	v, err := input.DecodeJSON(strings.NewReader(`{"z":1,"a":{"y":true,"b":null}}`))
+	require.NoError(t, err)
+	requireJSON(t, `{"z":1,"a":{"y":true,"b":null}}`, v)
+
+	v, err = input.DecodeJSON(strings.NewReader("{\"a\":1}\n{\"a\":2}\n[3] \"x\""))
+	require.NoError(t, err)
+	require.IsType(t, input.Stream{}, v)
+	requireJSON(t, `[{"a":1},{"a":2},[3],"x"]`, v)
+
+	_, err = input.DecodeJSON(strings.NewReader("{\"a\":1}\n{"))
+	require.Error(t, err)
+
+	_, err = input.DecodeJSON(strings.NewReader(`{"a" 1}`))
+	require.Error(t, err)
+
+	_, err = input.DecodeJSON(strings.NewReader(" "))
+	require.ErrorIs(t, err, io.EOF)
+}
+
+func TestDecodeNDJSON(t *testing.T) {
+	v, err := input.DecodeNDJSON(strings.NewReader("{\"a\":1}\n"))
+	require.NoError(t, err)
+	require.IsType(t, input.Stream{}, v)
+	requireJSON(t, `[{"a":1}]`, v)
+}
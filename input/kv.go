@@ -0,0 +1,51 @@
+// Copyright 2026 The gutenfmt authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package input
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+
+	"github.com/abc-inc/gutenfmt/ordered"
+)
+
+// DecodeKV decodes lines of name and value pairs, separated by the first
+// equal sign, colon or tab, into an *ordered.Map.
+// Lines without separator are skipped. If a name occurs multiple times, the
+// last value wins, but the name keeps the position of its first occurrence.
+// If no pair is found at all, nil is returned.
+func DecodeKV(r io.Reader) (any, error) {
+	kv := ordered.NewMap(0)
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		if idx := bytes.IndexAny(s.Bytes(), "=:\t"); idx > 0 {
+			kv.Set(string(s.Bytes()[:idx]), string(s.Bytes()[idx+1:]))
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	if kv.Len() == 0 {
+		return nil, nil
+	}
+	return kv, nil
+}
+
+// SniffKV accepts any input, because every line without separator is skipped.
+// Hence, it should be registered last.
+func SniffKV(_ []byte) bool {
+	return true
+}
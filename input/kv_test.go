@@ -0,0 +1,33 @@
+// Copyright 2026 The gutenfmt authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package input_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/abc-inc/gutenfmt/input"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeKV(t *testing.T) {
+	v, err := input.DecodeKV(strings.NewReader("URL=http://localhost:8080\nJAVA_HOME=/opt/java\n=skip\n"))
+	require.NoError(t, err)
+	requireJSON(t, `{"URL":"http://localhost:8080","JAVA_HOME":"/opt/java"}`, v)
+
+	v, err = input.DecodeKV(strings.NewReader("no pairs"))
+	require.NoError(t, err)
+	require.Nil(t, v)
+}
@@ -0,0 +1,184 @@
+// Copyright 2026 The gutenfmt authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package input
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/abc-inc/gutenfmt/ordered"
+	"gopkg.in/yaml.v3"
+)
+
+// DecodeYAML decodes a YAML document or a stream of documents separated by "---".
+//
+// A single document is returned as is, whereas multiple documents are returned
+// as Stream. Mappings are decoded as *ordered.Map to preserve the order of
+// their keys, and scalars are normalized by Normalize, so that they can be
+// processed by jq or JMESPath.
+func DecodeYAML(r io.Reader) (any, error) {
+	var s Stream
+	d := yaml.NewDecoder(r)
+	for {
+		n := &yaml.Node{}
+		if err := d.Decode(n); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		v, err := fromNode(n)
+		if err != nil {
+			return nil, err
+		}
+		s = append(s, v)
+	}
+
+	switch len(s) {
+	case 0:
+		return nil, nil
+	case 1:
+		return s[0], nil
+	default:
+		return s, nil
+	}
+}
+
+// fromNode converts a YAML node to a generic value.
+func fromNode(n *yaml.Node) (any, error) {
+	switch n.Kind {
+	case yaml.DocumentNode:
+		if len(n.Content) == 0 {
+			return nil, nil
+		}
+		return fromNode(n.Content[0])
+	case yaml.AliasNode:
+		return fromNode(n.Alias)
+	case yaml.SequenceNode:
+		s := make([]any, len(n.Content))
+		for idx, c := range n.Content {
+			v, err := fromNode(c)
+			if err != nil {
+				return nil, err
+			}
+			s[idx] = v
+		}
+		return s, nil
+	case yaml.MappingNode:
+		m := ordered.NewMap(len(n.Content) / 2)
+		for idx := 0; idx+1 < len(n.Content); idx += 2 {
+			v, err := fromNode(n.Content[idx+1])
+			if err != nil {
+				return nil, err
+			}
+			if n.Content[idx].Tag == "!!merge" {
+				merge(m, v)
+				continue
+			}
+			k := n.Content[idx].Value
+			if n.Content[idx].Kind != yaml.ScalarNode {
+				kv, err := fromNode(n.Content[idx])
+				if err != nil {
+					return nil, err
+				}
+				k = fmt.Sprint(kv)
+			}
+			m.Set(k, v)
+		}
+		return m, nil
+	default:
+		var v any
+		if err := n.Decode(&v); err != nil {
+			return nil, err
+		}
+		return Normalize(v), nil
+	}
+}
+
+// merge adds all entries of the merged mapping(s) v, which are not yet present in m.
+func merge(m *ordered.Map, v any) {
+	switch t := v.(type) {
+	case *ordered.Map:
+		for _, k := range t.Keys() {
+			if _, ok := m.Get(k); !ok {
+				e, _ := t.Get(k)
+				m.Set(k, e)
+			}
+		}
+	case []any:
+		for _, e := range t {
+			merge(m, e)
+		}
+	}
+}
+
+// Normalize converts nested maps with arbitrary keys e.g., map[any]any, to
+// map[string]any, timestamps to RFC 3339 strings and integers, which exceed
+// the range of int, to float64, recursively.
+// Other values are returned as is.
+func Normalize(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, e := range t {
+			t[k] = Normalize(e)
+		}
+		return t
+	case map[any]any:
+		m := make(map[string]any, len(t))
+		for k, e := range t {
+			m[fmt.Sprint(k)] = Normalize(e)
+		}
+		return m
+	case []any:
+		for idx, e := range t {
+			t[idx] = Normalize(e)
+		}
+		return t
+	case uint64:
+		// yaml.v3 only uses uint64 for values exceeding the range of int.
+		return float64(t)
+	case time.Time:
+		return t.Format(time.RFC3339Nano)
+	default:
+		return v
+	}
+}
+
+// SniffYAML reports whether b looks like YAML, which cannot be decoded as
+// name and value pairs, i.e., it starts with a directive or document marker,
+// a sequence entry or contains indented lines or further documents.
+func SniffYAML(b []byte) bool {
+	s := bufio.NewScanner(bytes.NewReader(b))
+	first := true
+	for s.Scan() {
+		l := s.Bytes()
+		t := bytes.TrimSpace(l)
+		if len(t) == 0 || t[0] == '#' {
+			continue
+		}
+		if first && (bytes.HasPrefix(t, []byte("%YAML")) || bytes.HasPrefix(t, []byte("- "))) {
+			return true
+		}
+		if bytes.Equal(t, []byte("---")) || bytes.HasPrefix(l, []byte("--- ")) ||
+			(l[0] == ' ' || l[0] == '\t') && !first {
+			return true
+		}
+		first = false
+	}
+	return false
+}
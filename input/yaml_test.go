@@ -0,0 +1,93 @@
+// Copyright 2026 The gutenfmt authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package input_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/abc-inc/gutenfmt/input"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeYAML(t *testing.T) {
+	v, err := input.DecodeYAML(strings.NewReader(heredoc.Doc(`
+		image:
+		  tag: 1.2
+		  pullPolicy: IfNotPresent
+		ports: [80, 443]
+		1: one
+		true: yes
+		2.5: c
+	`)))
+	require.NoError(t, err)
+	requireJSON(t, `{"image":{"tag":1.2,"pullPolicy":"IfNotPresent"},"ports":[80,443],"1":"one","true":"yes","2.5":"c"}`, v)
+}
+
+func TestDecodeYAML_multiDoc(t *testing.T) {
+	v, err := input.DecodeYAML(strings.NewReader(heredoc.Doc(`
+		---
+		kind: Service
+		---
+		kind: Deployment
+		created: 2026-01-02T03:04:05Z
+		big: 18446744073709551615
+	`)))
+	require.NoError(t, err)
+	require.IsType(t, input.Stream{}, v)
+	requireJSON(t, `[{"kind":"Service"},{"kind":"Deployment","created":"2026-01-02T03:04:05Z","big":18446744073709552000}]`, v)
+
+	v, err = input.DecodeYAML(strings.NewReader(""))
+	require.NoError(t, err)
+	require.Nil(t, v)
+
+	_, err = input.DecodeYAML(strings.NewReader("a: [\n"))
+	require.Error(t, err)
+}
+
+func TestDecodeYAML_merge(t *testing.T) {
+	v, err := input.DecodeYAML(strings.NewReader(heredoc.Doc(`
+		base: &base
+		  a: 1
+		  b: 2
+		ext:
+		  b: 3
+		  <<: *base
+		  c: 4
+	`)))
+	require.NoError(t, err)
+	requireJSON(t, `{"base":{"a":1,"b":2},"ext":{"b":3,"a":1,"c":4}}`, v)
+}
+
+func TestNormalize(t *testing.T) {
+	ts := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	require.Equal(t, []any{map[string]any{"1": "2026-01-02T03:04:05Z"}},
+		input.Normalize([]any{map[any]any{1: ts}}))
+}
+
+func TestSniffYAML(t *testing.T) {
+	for _, s := range []string{"---\na: 1", "%YAML 1.2\n---\n", "# c\n- a\n- b", "a:\n  b: 1", "a: 1\n---\na: 2"} {
+		require.True(t, input.SniffYAML([]byte(s)), s)
+	}
+	for _, s := range []string{"", "a: 1\nb: 2", "a=1", " a: 1"} {
+		require.False(t, input.SniffYAML([]byte(s)), s)
+	}
+
+	v, err := input.Decode(strings.NewReader("a:\n  b: 1\n"), input.Auto)
+	require.NoError(t, err)
+	requireJSON(t, `{"a":{"b":1}}`, v)
+}
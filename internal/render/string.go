@@ -47,7 +47,10 @@ func ToString(i any) string {
 	case reflect.Ptr:
 		return ToString(reflect.Indirect(reflect.ValueOf(i)).Interface())
 	case reflect.String:
-		return i.(string)
+		if s, ok := i.(string); ok {
+			return s
+		}
+		return fmt.Sprint(i)
 	default:
 		return fmt.Sprint(i)
 	}
@@ -15,6 +15,7 @@
 package render_test
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/abc-inc/gutenfmt/internal/render"
@@ -24,6 +25,7 @@ import (
 func TestToString(t *testing.T) {
 	assert.Equal(t, "", render.ToString(nil))
 	assert.Equal(t, "x", render.ToString("x"))
+	assert.Equal(t, "1.5", render.ToString(json.Number("1.5")))
 	assert.Equal(t, "true", render.ToString(true))
 
 	assert.Equal(t, "", render.ToString([]int{}))
@@ -0,0 +1,287 @@
+// Copyright 2026 The gutenfmt authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package table converts arbitrary values to a two-dimensional representation,
+// which is shared by Writers that need access to individual cells.
+package table
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/abc-inc/gutenfmt/formatter"
+	"github.com/abc-inc/gutenfmt/internal/render"
+	"github.com/abc-inc/gutenfmt/meta"
+	"github.com/abc-inc/gutenfmt/ordered"
+)
+
+// Table holds rows of cells with an optional header.
+type Table struct {
+	// Header holds the column names or nil, if the Table consists of
+	// name and value pairs or scalar values.
+	Header []string
+	// Rows holds the unformatted cell values.
+	Rows [][]any
+}
+
+// Options controls how values are converted to a Table.
+type Options struct {
+	// SortKeys sorts the keys of maps, which do not define an order.
+	SortKeys bool
+	// Flatten expands nested maps, structs, slices and arrays into columns
+	// named by their path e.g., "parent.child" or "items.0".
+	Flatten bool
+	// MaxDepth limits the number of nested levels, which are flattened, 0 means unlimited.
+	// Values below are kept as is.
+	MaxDepth int
+	// Separator joins the names of nested values, "." if empty.
+	Separator string
+	// Brackets names array elements like "items[0]" instead of "items.0".
+	Brackets bool
+	// Missing is the value of cells, whose record does not have the column's key.
+	Missing any
+}
+
+// From converts a value to a Table.
+//
+// Slices of maps and structs result in one row per element and one column per
+// key or field. A single map or struct results in one row per entry, each
+// consisting of name and value. Any other slice or array results in a single
+// column. It returns false, if i is not any of those e.g., a scalar value.
+func From(i any, o Options) (*Table, bool) {
+	v := reflect.ValueOf(i)
+	if IsRecord(i) {
+		r := o.record(i)
+		t := &Table{}
+		for _, k := range r.Keys() {
+			e, _ := r.Get(k)
+			t.Rows = append(t.Rows, []any{k, e})
+		}
+		return t, true
+	} else if k := v.Kind(); k == reflect.Ptr {
+		if v.IsNil() {
+			return nil, false
+		}
+		return From(v.Elem().Interface(), o)
+	} else if k != reflect.Slice && k != reflect.Array {
+		return nil, false
+	}
+
+	if v.Len() == 0 || !IsRecord(v.Index(0).Interface()) {
+		if isStructType(v.Type().Elem()) {
+			// Even without elements, the header is known.
+			return &Table{Header: o.header(v.Type().Elem())}, true
+		}
+		t := &Table{}
+		for idx := 0; idx < v.Len(); idx++ {
+			t.Rows = append(t.Rows, []any{v.Index(idx).Interface()})
+		}
+		return t, true
+	}
+
+	rs := make([]*ordered.Map, v.Len())
+	for idx := range rs {
+		rs[idx] = o.record(v.Index(idx).Interface())
+	}
+
+	t := &Table{Header: o.schema(v, rs)}
+	for _, r := range rs {
+		row := make([]any, len(t.Header))
+		for idx, k := range t.Header {
+			var ok bool
+			if row[idx], ok = r.Get(k); !ok {
+				row[idx] = o.Missing
+			}
+		}
+		t.Rows = append(t.Rows, row)
+	}
+	return t, true
+}
+
+// Records converts a slice or array of records to ordered.Maps, whose keys
+// are in the order of the Table's header.
+// Unlike the Table's rows, the maps lack the keys a record does not have.
+// It returns false, if i is not a slice or array of records.
+func Records(i any, o Options) ([]string, []*ordered.Map, bool) {
+	o.Missing = absent{}
+	t, ok := From(i, o)
+	if !ok || t.Header == nil {
+		return nil, nil, false
+	}
+
+	rs := make([]*ordered.Map, len(t.Rows))
+	for idx, row := range t.Rows {
+		rs[idx] = ordered.NewMap(len(t.Header))
+		for c, k := range t.Header {
+			if _, ok := row[c].(absent); !ok {
+				rs[idx].Set(k, row[c])
+			}
+		}
+	}
+	return t.Header, rs, true
+}
+
+// absent marks cells of keys, which are not present in a record.
+type absent struct{}
+
+// schema returns the union of the keys of all records in order of their first occurrence.
+// If requested, the keys are sorted, unless any element defines the order
+// i.e., it is an ordered.Map or a struct.
+func (o Options) schema(v reflect.Value, rs []*ordered.Map) []string {
+	var ks []string
+	seen := map[string]bool{}
+	for _, r := range rs {
+		for _, k := range r.Keys() {
+			if !seen[k] {
+				seen[k] = true
+				ks = append(ks, k)
+			}
+		}
+	}
+
+	if o.SortKeys {
+		for idx := 0; idx < v.Len(); idx++ {
+			e := reflect.Indirect(reflect.ValueOf(v.Index(idx).Interface()))
+			if e.Kind() != reflect.Map && e.Kind() != reflect.Invalid {
+				return ks
+			}
+		}
+		sort.Strings(ks)
+	}
+	return ks
+}
+
+// IsRecord returns true if i is a map, an ordered.Map, a struct with at least
+// one field recognized by meta.Resolve, or a pointer to any of those.
+// Structs without such fields e.g., time.Time, are treated as scalar values.
+func IsRecord(i any) bool {
+	if formatter.IsMap(i) {
+		return true
+	}
+	v := reflect.ValueOf(i)
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	return v.Kind() == reflect.Struct && isStructType(v.Type())
+}
+
+// isStructType returns true if typ is a struct or a pointer to a struct with
+// at least one field recognized by meta.Resolve.
+func isStructType(typ reflect.Type) bool {
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	return typ.Kind() == reflect.Struct && len(meta.Resolve(typ)) > 0
+}
+
+// header returns the column names of a struct type.
+func (o Options) header(typ reflect.Type) []string {
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	var hdr []string
+	for _, f := range meta.Resolve(typ) {
+		hdr = append(hdr, f.Name)
+	}
+	return hdr
+}
+
+// Record converts a map or struct to an ordered.Map, whose keys are the map
+// keys or the field names determined by meta.Resolve.
+// It returns false, if i is not a record.
+func Record(i any, o Options) (*ordered.Map, bool) {
+	if !IsRecord(i) {
+		return nil, false
+	}
+	return o.record(i), true
+}
+
+// record converts a map or struct to an ordered.Map, which is flattened, if requested.
+func (o Options) record(i any) *ordered.Map {
+	r := ordered.NewMap(0)
+	o.collect(r, "", i, 0)
+	return r
+}
+
+// collect adds all entries of the map or struct i, which is nested depth
+// levels below the top-level record, to r.
+// Nested records are flattened, if requested, or added as is otherwise.
+func (o Options) collect(r *ordered.Map, prefix string, i any, depth int) {
+	add := func(k string, e any) {
+		if o.Flatten {
+			o.flatten(r, prefix+k, e, depth)
+		} else {
+			r.Set(prefix+k, e)
+		}
+	}
+
+	if formatter.IsMap(i) {
+		for _, k := range formatter.MapKeys(i, o.SortKeys) {
+			var e any
+			if mv := formatter.MapIndex(i, k); mv.IsValid() {
+				e = mv.Interface()
+			}
+			add(render.ToString(k.Interface()), e)
+		}
+		return
+	}
+
+	v := reflect.Indirect(reflect.ValueOf(i))
+	if v.Kind() != reflect.Struct {
+		// e.g., nil pointers or scalar values in a slice of records
+		return
+	}
+	for _, f := range meta.Resolve(v.Type()) {
+		add(f.Name, v.FieldByName(f.Field).Interface())
+	}
+}
+
+// flatten adds e to r, if it is a scalar value or the maximum depth is
+// reached, or all of its nested values otherwise.
+func (o Options) flatten(r *ordered.Map, path string, e any, depth int) {
+	if o.MaxDepth > 0 && depth >= o.MaxDepth {
+		r.Set(path, e)
+		return
+	}
+	if IsRecord(e) {
+		o.collect(r, path+o.separator(), e, depth+1)
+		return
+	}
+
+	v := reflect.ValueOf(e)
+	if k := v.Kind(); (k == reflect.Slice || k == reflect.Array) && v.Type().Elem().Kind() != reflect.Uint8 {
+		for idx := 0; idx < v.Len(); idx++ {
+			o.flatten(r, o.index(path, idx), v.Index(idx).Interface(), depth+1)
+		}
+		return
+	}
+	r.Set(path, e)
+}
+
+// separator returns the separator of nested names.
+func (o Options) separator() string {
+	if o.Separator == "" {
+		return "."
+	}
+	return o.Separator
+}
+
+// index returns the name of an array element.
+func (o Options) index(path string, idx int) string {
+	if o.Brackets {
+		return fmt.Sprintf("%s[%d]", path, idx)
+	}
+	return fmt.Sprintf("%s%s%d", path, o.separator(), idx)
+}
@@ -0,0 +1,127 @@
+// Copyright 2026 The gutenfmt authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/abc-inc/gutenfmt/internal/table"
+	"github.com/abc-inc/gutenfmt/ordered"
+	"github.com/stretchr/testify/require"
+)
+
+type user struct {
+	Name    string `json:"name"`
+	Secret  string `json:"-"`
+	Address *address
+	Tags    []string `json:"tags"`
+	Created time.Time
+}
+
+type address struct {
+	City string `json:"city"`
+}
+
+func TestFrom(t *testing.T) {
+	ts := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	u := user{"Jane", "x", &address{"Vienna"}, []string{"a", "b"}, ts}
+
+	tbl, ok := table.From([]*user{&u, nil}, table.Options{})
+	require.True(t, ok)
+	require.Equal(t, []string{"name", "Address", "tags", "Created"}, tbl.Header)
+	require.Equal(t, [][]any{{"Jane", &address{"Vienna"}, []string{"a", "b"}, ts}, {nil, nil, nil, nil}}, tbl.Rows)
+
+	tbl, ok = table.From([]user{u}, table.Options{Flatten: true})
+	require.True(t, ok)
+	require.Equal(t, []string{"name", "Address.city", "tags.0", "tags.1", "Created"}, tbl.Header)
+	require.Equal(t, [][]any{{"Jane", "Vienna", "a", "b", ts}}, tbl.Rows)
+
+	tbl, ok = table.From([]user{}, table.Options{})
+	require.True(t, ok)
+	require.Equal(t, []string{"name", "Address", "tags", "Created"}, tbl.Header)
+	require.Empty(t, tbl.Rows)
+}
+
+func TestFrom_maps(t *testing.T) {
+	m := ordered.NewMap(2)
+	m.Set("z", 1)
+	m.Set("a", map[string]any{"y": 2, "b": []any{3}})
+
+	tbl, ok := table.From(m, table.Options{})
+	require.True(t, ok)
+	require.Nil(t, tbl.Header)
+	require.Equal(t, [][]any{{"z", 1}, {"a", map[string]any{"y": 2, "b": []any{3}}}}, tbl.Rows)
+
+	tbl, ok = table.From([]any{m}, table.Options{Flatten: true, SortKeys: true})
+	require.True(t, ok)
+	require.Equal(t, []string{"z", "a.b.0", "a.y"}, tbl.Header)
+	require.Equal(t, [][]any{{1, 3, 2}}, tbl.Rows)
+
+	tbl, ok = table.From([]map[string]int{{"b": 1, "a": 2}}, table.Options{SortKeys: true})
+	require.True(t, ok)
+	require.Equal(t, []string{"a", "b"}, tbl.Header)
+	require.Equal(t, [][]any{{2, 1}}, tbl.Rows)
+}
+
+func TestFrom_scalars(t *testing.T) {
+	tbl, ok := table.From([]any{1, "a", nil}, table.Options{})
+	require.True(t, ok)
+	require.Nil(t, tbl.Header)
+	require.Equal(t, [][]any{{1}, {"a"}, {nil}}, tbl.Rows)
+
+	_, ok = table.From(42, table.Options{})
+	require.False(t, ok)
+	_, ok = table.From(time.Now(), table.Options{})
+	require.False(t, ok)
+	_, ok = table.From((*user)(nil), table.Options{})
+	require.False(t, ok)
+}
+
+func TestFrom_flattenOptions(t *testing.T) {
+	m := ordered.NewMap(2)
+	m.Set("id", 1)
+	m.Set("a", map[string]any{"b": map[string]any{"c": 2}, "items": []any{"x", []any{"y"}}})
+
+	tbl, ok := table.From([]any{m}, table.Options{Flatten: true, SortKeys: true, Separator: "/", Brackets: true})
+	require.True(t, ok)
+	require.Equal(t, []string{"id", "a/b/c", "a/items[0]", "a/items[1][0]"}, tbl.Header)
+	require.Equal(t, [][]any{{1, 2, "x", "y"}}, tbl.Rows)
+
+	tbl, ok = table.From([]any{m}, table.Options{Flatten: true, SortKeys: true, MaxDepth: 2})
+	require.True(t, ok)
+	require.Equal(t, []string{"id", "a.b.c", "a.items.0", "a.items.1"}, tbl.Header)
+	require.Equal(t, [][]any{{1, 2, "x", []any{"y"}}}, tbl.Rows)
+
+	r, ok := table.Record(m, table.Options{Flatten: true, SortKeys: true, MaxDepth: 1})
+	require.True(t, ok)
+	require.Equal(t, []string{"id", "a.b", "a.items"}, r.Keys())
+}
+
+func TestRecords(t *testing.T) {
+	hdr, rs, ok := table.Records([]any{map[string]any{"b": 1, "a": nil}, map[string]any{"c": 2}}, table.Options{SortKeys: true})
+	require.True(t, ok)
+	require.Equal(t, []string{"a", "b", "c"}, hdr)
+	require.Len(t, rs, 2)
+	require.Equal(t, []string{"a", "b"}, rs[0].Keys())
+	require.Equal(t, []string{"c"}, rs[1].Keys())
+	v, _ := rs[0].Get("a")
+	require.Nil(t, v)
+
+	_, _, ok = table.Records([]any{1, 2}, table.Options{})
+	require.False(t, ok)
+	_, _, ok = table.Records(map[string]any{"a": 1}, table.Options{})
+	require.False(t, ok)
+}
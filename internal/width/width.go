@@ -0,0 +1,230 @@
+// Copyright 2026 The gutenfmt authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package width measures the display width of strings in a terminal.
+//
+// Wide characters like CJK ideographs and most emoji occupy two columns,
+// combining marks and other zero-width characters none. ANSI escape sequences
+// e.g., colour codes, are not visible and therefore do not count either.
+package width
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	esc = '\x1b'
+	zwj = '\u200d'
+)
+
+// wide holds the ranges of East Asian Wide (W) and Fullwidth (F) characters,
+// including emoji presentation sequences.
+var wide = [][2]rune{
+	{0x1100, 0x115F}, {0x231A, 0x231B}, {0x2329, 0x232A}, {0x23E9, 0x23EC}, {0x23F0, 0x23F0},
+	{0x23F3, 0x23F3}, {0x25FD, 0x25FE}, {0x2614, 0x2615}, {0x2648, 0x2653}, {0x267F, 0x267F},
+	{0x2693, 0x2693}, {0x26A1, 0x26A1}, {0x26AA, 0x26AB}, {0x26BD, 0x26BE}, {0x26C4, 0x26C5},
+	{0x26CE, 0x26CE}, {0x26D4, 0x26D4}, {0x26EA, 0x26EA}, {0x26F2, 0x26F3}, {0x26F5, 0x26F5},
+	{0x26FA, 0x26FA}, {0x26FD, 0x26FD}, {0x2705, 0x2705}, {0x270A, 0x270B}, {0x2728, 0x2728},
+	{0x274C, 0x274C}, {0x274E, 0x274E}, {0x2753, 0x2755}, {0x2757, 0x2757}, {0x2795, 0x2797},
+	{0x27B0, 0x27B0}, {0x27BF, 0x27BF}, {0x2B1B, 0x2B1C}, {0x2B50, 0x2B50}, {0x2B55, 0x2B55},
+	{0x2E80, 0x303E}, {0x3041, 0x33FF}, {0x3400, 0x4DBF}, {0x4E00, 0x9FFF}, {0xA000, 0xA4CF},
+	{0xA960, 0xA97F}, {0xAC00, 0xD7A3}, {0xF900, 0xFAFF}, {0xFE10, 0xFE19}, {0xFE30, 0xFE6F},
+	{0xFF00, 0xFF60}, {0xFFE0, 0xFFE6}, {0x16FE0, 0x16FE4}, {0x17000, 0x18AFF}, {0x1B000, 0x1B2FF},
+	{0x1F004, 0x1F004}, {0x1F0CF, 0x1F0CF}, {0x1F18E, 0x1F18E}, {0x1F191, 0x1F19A}, {0x1F200, 0x1F251},
+	{0x1F300, 0x1F320}, {0x1F32D, 0x1F335}, {0x1F337, 0x1F37C}, {0x1F37E, 0x1F393}, {0x1F3A0, 0x1F3CA},
+	{0x1F3CF, 0x1F3D3}, {0x1F3E0, 0x1F3F0}, {0x1F3F4, 0x1F3F4}, {0x1F3F8, 0x1F43E}, {0x1F440, 0x1F440},
+	{0x1F442, 0x1F4FC}, {0x1F4FF, 0x1F53D}, {0x1F54B, 0x1F54E}, {0x1F550, 0x1F567}, {0x1F57A, 0x1F57A},
+	{0x1F595, 0x1F596}, {0x1F5A4, 0x1F5A4}, {0x1F5FB, 0x1F64F}, {0x1F680, 0x1F6C5}, {0x1F6CC, 0x1F6CC},
+	{0x1F6D0, 0x1F6D2}, {0x1F6D5, 0x1F6D7}, {0x1F6DC, 0x1F6DF}, {0x1F6EB, 0x1F6EC}, {0x1F6F4, 0x1F6FC},
+	{0x1F7E0, 0x1F7EB}, {0x1F7F0, 0x1F7F0}, {0x1F90C, 0x1F93A}, {0x1F93C, 0x1F945}, {0x1F947, 0x1F9FF},
+	{0x1FA70, 0x1FAFF}, {0x20000, 0x2FFFD}, {0x30000, 0x3FFFD},
+}
+
+// Rune returns the number of columns occupied by r.
+func Rune(r rune) int {
+	switch {
+	case r < 0x20 || (r >= 0x7F && r < 0xA0):
+		return 0
+	case r < 0x300:
+		return 1
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf) || (r >= 0x1160 && r <= 0x11FF):
+		return 0
+	}
+	idx := sort.Search(len(wide), func(i int) bool { return wide[i][1] >= r })
+	if idx < len(wide) && wide[idx][0] <= r {
+		return 2
+	}
+	return 1
+}
+
+// String returns the number of columns occupied by s, ignoring ANSI escape sequences.
+//
+// Characters joined by a zero width joiner and emoji modifiers are assumed to
+// be rendered as a single glyph.
+func String(s string) (n int) {
+	prev := rune(0)
+	for i := 0; i < len(s); {
+		if l := escapeLen(s[i:]); l > 0 {
+			i += l
+			continue
+		}
+		r, l := utf8.DecodeRuneInString(s[i:])
+		i += l
+		if prev == zwj || (prev != 0 && r >= 0x1F3FB && r <= 0x1F3FF) {
+			prev = r
+			continue
+		}
+		n += Rune(r)
+		prev = r
+	}
+	return n
+}
+
+// Strip removes all ANSI escape sequences from s.
+func Strip(s string) string {
+	if !strings.ContainsRune(s, esc) {
+		return s
+	}
+	b := strings.Builder{}
+	for i := 0; i < len(s); {
+		if l := escapeLen(s[i:]); l > 0 {
+			i += l
+			continue
+		}
+		_, l := utf8.DecodeRuneInString(s[i:])
+		b.WriteString(s[i : i+l])
+		i += l
+	}
+	return b.String()
+}
+
+// Truncate shortens s to at most w columns, including tail e.g., "…", which
+// is appended if s is longer. Escape sequences are kept and, if s contains any,
+// the attributes are reset after truncation.
+func Truncate(s string, w int, tail string) string {
+	if String(s) <= w {
+		return s
+	}
+	w -= String(tail)
+	b := strings.Builder{}
+	n, escaped := 0, false
+	for i := 0; i < len(s); {
+		if l := escapeLen(s[i:]); l > 0 {
+			b.WriteString(s[i : i+l])
+			i, escaped = i+l, true
+			continue
+		}
+		r, l := utf8.DecodeRuneInString(s[i:])
+		if n+Rune(r) > w {
+			break
+		}
+		b.WriteString(s[i : i+l])
+		n += Rune(r)
+		i += l
+	}
+	b.WriteString(tail)
+	if escaped {
+		b.WriteString("\x1b[0m")
+	}
+	return b.String()
+}
+
+// Wrap breaks s into lines of at most w columns.
+//
+// Lines are broken at spaces, if possible. Words longer than w are split.
+// Existing line breaks are preserved.
+func Wrap(s string, w int) (ls []string) {
+	if w <= 0 {
+		return strings.Split(s, "\n")
+	}
+	for _, p := range strings.Split(s, "\n") {
+		ls = append(ls, wrapLine(p, w)...)
+	}
+	return ls
+}
+
+// wrapLine breaks a single line into lines of at most w columns.
+func wrapLine(s string, w int) (ls []string) {
+	line, n := "", 0
+	for _, word := range strings.Split(s, " ") {
+		ww := String(word)
+		if n > 0 && n+1+ww <= w {
+			line, n = line+" "+word, n+1+ww
+			continue
+		} else if n > 0 || line != "" {
+			ls = append(ls, line)
+			line, n = "", 0
+		}
+		for ww > w {
+			head, rest := cut(word, w)
+			if rest == "" {
+				break
+			}
+			ls = append(ls, head)
+			word, ww = rest, String(rest)
+		}
+		line, n = word, ww
+	}
+	return append(ls, line)
+}
+
+// cut splits s after at most w columns, but at least after the first character.
+func cut(s string, w int) (string, string) {
+	n := 0
+	for i := 0; i < len(s); {
+		if l := escapeLen(s[i:]); l > 0 {
+			i += l
+			continue
+		}
+		r, l := utf8.DecodeRuneInString(s[i:])
+		if n > 0 && n+Rune(r) > w {
+			return s[:i], s[i:]
+		}
+		n += Rune(r)
+		i += l
+	}
+	return s, ""
+}
+
+// escapeLen returns the length of the ANSI escape sequence at the beginning of s or 0.
+func escapeLen(s string) int {
+	if len(s) < 2 || s[0] != esc {
+		return 0
+	}
+	switch s[1] {
+	case '[':
+		// Control Sequence Introducer: parameters and intermediate bytes followed by a final byte.
+		for i := 2; i < len(s); i++ {
+			if s[i] >= 0x40 && s[i] <= 0x7E {
+				return i + 1
+			}
+		}
+		return len(s)
+	case ']':
+		// Operating System Command: terminated by BEL or ST.
+		for i := 2; i < len(s); i++ {
+			if s[i] == '\a' {
+				return i + 1
+			} else if s[i] == esc && i+1 < len(s) && s[i+1] == '\\' {
+				return i + 2
+			}
+		}
+		return len(s)
+	default:
+		return 2
+	}
+}
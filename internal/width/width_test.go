@@ -0,0 +1,69 @@
+// Copyright 2026 The gutenfmt authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package width_test
+
+import (
+	"testing"
+
+	"github.com/abc-inc/gutenfmt/internal/width"
+	"github.com/stretchr/testify/require"
+)
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want int
+	}{
+		{"empty", "", 0},
+		{"ascii", "hello", 5},
+		{"latin", "Größe", 5},
+		{"combining", "é", 1},
+		{"cjk", "日本語", 6},
+		{"hangul", "한국", 4},
+		{"fullwidth", "ＡＢ", 4},
+		{"emoji", "👍", 2},
+		{"emoji_modifier", "👍🏽", 2},
+		{"emoji_zwj", "👩‍💻", 2},
+		{"csi", "\x1b[1;31mred\x1b[0m", 3},
+		{"osc", "\x1b]8;;https://example.com\x1b\\link\x1b]8;;\x1b\\", 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, width.String(tt.s))
+		})
+	}
+}
+
+func TestStrip(t *testing.T) {
+	require.Equal(t, "red", width.Strip("\x1b[31mred\x1b[0m"))
+	require.Equal(t, "plain", width.Strip("plain"))
+}
+
+func TestTruncate(t *testing.T) {
+	require.Equal(t, "short", width.Truncate("short", 5, "…"))
+	require.Equal(t, "shor…", width.Truncate("shorter", 5, "…"))
+	require.Equal(t, "日本…", width.Truncate("日本語です", 5, "…"))
+	require.Equal(t, "日…", width.Truncate("日本語です", 4, "…"))
+	require.Equal(t, "\x1b[31mre…\x1b[0m", width.Truncate("\x1b[31mred color\x1b[0m", 3, "…"))
+}
+
+func TestWrap(t *testing.T) {
+	require.Equal(t, []string{"the quick", "brown fox"}, width.Wrap("the quick brown fox", 10))
+	require.Equal(t, []string{"abcd", "efgh", "ij"}, width.Wrap("abcdefghij", 4))
+	require.Equal(t, []string{"日本", "語"}, width.Wrap("日本語", 5))
+	require.Equal(t, []string{"a", "b c"}, width.Wrap("a\nb c", 3))
+	require.Equal(t, []string{"日", "本"}, width.Wrap("日本", 1))
+}
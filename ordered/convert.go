@@ -0,0 +1,146 @@
+// Copyright 2026 The gutenfmt authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ordered
+
+import "reflect"
+
+// Plain returns v with every Map replaced by a regular map[string]any, recursively.
+// It is used to pass values to libraries, which do not know about Map.
+func Plain(v any) any {
+	return (*Orders)(nil).Plain(v)
+}
+
+// Orders holds the key order of regular maps, which is identified by the maps
+// themselves rather than by their content.
+// Together with Restore, it allows to recover the key order after values were
+// processed by libraries, which do not know about Map. Hence, a map keeps its
+// order only as long as it is passed through unchanged.
+type Orders struct {
+	keys map[uintptr][]string
+	// maps keeps the maps alive, so that their addresses are not reused.
+	maps []map[string]any
+}
+
+// NewOrders creates a new, empty Orders.
+func NewOrders() *Orders {
+	return &Orders{keys: map[uintptr][]string{}}
+}
+
+// Len returns the number of maps, whose key order is known.
+func (o *Orders) Len() int {
+	if o == nil {
+		return 0
+	}
+	return len(o.keys)
+}
+
+// Set records the key order of m.
+// If o is nil, nothing is recorded.
+func (o *Orders) Set(m map[string]any, ks []string) {
+	if o == nil || m == nil {
+		return
+	}
+	o.keys[reflect.ValueOf(m).Pointer()] = ks
+	o.maps = append(o.maps, m)
+}
+
+// Plain is like the function Plain, but records the key order of every Map.
+func (o *Orders) Plain(v any) any {
+	switch t := v.(type) {
+	case *Map:
+		if t == nil {
+			return nil
+		}
+		return o.Plain(*t)
+	case Map:
+		m := make(map[string]any, t.Len())
+		for k, e := range t.vals {
+			m[k] = o.Plain(e)
+		}
+		o.Set(m, t.keys)
+		return m
+	case map[string]any:
+		m := make(map[string]any, len(t))
+		for k, e := range t {
+			m[k] = o.Plain(e)
+		}
+		return m
+	case []any:
+		s := make([]any, len(t))
+		for idx, e := range t {
+			s[idx] = o.Plain(e)
+		}
+		return s
+	default:
+		return v
+	}
+}
+
+// Restore returns v with every regular map[string]any replaced by a *Map,
+// recursively.
+// The keys of maps recorded in o come first in their recorded order, followed
+// by all other keys in lexical order. If o is nil, all keys are sorted.
+func (o *Orders) Restore(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		ks := o.keyOrder(t)
+		m := NewMap(len(ks))
+		for _, k := range ks {
+			m.Set(k, o.Restore(t[k]))
+		}
+		return m
+	case *Map:
+		if t == nil {
+			return t
+		}
+		return o.Restore(Plain(t))
+	case Map:
+		return o.Restore(Plain(t))
+	case []any:
+		s := make([]any, len(t))
+		for idx, e := range t {
+			s[idx] = o.Restore(e)
+		}
+		return s
+	default:
+		return v
+	}
+}
+
+// keyOrder returns the keys of m in their recorded order, followed by the
+// remaining keys in lexical order.
+func (o *Orders) keyOrder(m map[string]any) []string {
+	if o == nil {
+		return SortedKeys(m)
+	}
+	rks, ok := o.keys[reflect.ValueOf(m).Pointer()]
+	if !ok {
+		return SortedKeys(m)
+	}
+
+	ks := make([]string, 0, len(m))
+	seen := make(map[string]bool, len(m))
+	for _, k := range rks {
+		if _, ok := m[k]; ok && !seen[k] {
+			ks, seen[k] = append(ks, k), true
+		}
+	}
+	for _, k := range SortedKeys(m) {
+		if !seen[k] {
+			ks = append(ks, k)
+		}
+	}
+	return ks
+}
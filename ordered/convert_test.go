@@ -0,0 +1,74 @@
+// Copyright 2026 The gutenfmt authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ordered_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/abc-inc/gutenfmt/ordered"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlain(t *testing.T) {
+	m := &ordered.Map{}
+	require.NoError(t, json.Unmarshal([]byte(`{"b":[{"d":1}],"a":2}`), m))
+	require.Equal(t, map[string]any{"b": []any{map[string]any{"d": 1.0}}, "a": 2.0}, ordered.Plain(m))
+	require.Equal(t, []any{"x"}, ordered.Plain([]any{"x"}))
+}
+
+func TestOrders_Restore(t *testing.T) {
+	in, err := ordered.DecodeJSON(json.NewDecoder(strings.NewReader(`[{"z":{"y":1,"x":2}},{"c":3,"z":4}]`)))
+	require.NoError(t, err)
+	o := ordered.NewOrders()
+	p := o.Plain(in).([]any)
+	require.Equal(t, 3, o.Len())
+
+	// Unchanged maps keep their order, all others are sorted.
+	z := p[0].(map[string]any)["z"]
+	v := map[string]any{"z": z, "c": []any{p[1]}, "new": map[string]any{"z": 0, "a": 1}}
+	b, err := json.Marshal(o.Restore(v))
+	require.NoError(t, err)
+	require.Equal(t, `{"c":[{"c":3,"z":4}],"new":{"a":1,"z":0},"z":{"y":1,"x":2}}`, string(b))
+
+	b, err = json.Marshal((*ordered.Orders)(nil).Restore(v))
+	require.NoError(t, err)
+	require.Equal(t, `{"c":[{"c":3,"z":4}],"new":{"a":1,"z":0},"z":{"x":2,"y":1}}`, string(b))
+
+	// Keys, which are not recorded, are sorted after the others.
+	m := map[string]any{"b": 1, "a": 2, "d": 3, "c": 4}
+	o.Set(m, []string{"d", "b"})
+	b, err = json.Marshal(o.Restore(m))
+	require.NoError(t, err)
+	require.Equal(t, `{"d":3,"b":1,"a":2,"c":4}`, string(b))
+
+	require.Zero(t, (*ordered.Orders)(nil).Len())
+}
+
+func TestOrders_Restore_identity(t *testing.T) {
+	for _, s := range []string{
+		`{"b":{"a":1,"b":2}}`,
+		`[{"b":1,"a":2},{"a":3,"b":4}]`,
+		`{"x":[{"b":1,"a":2},{"a":3,"b":4}],"y":{"a":5,"b":6}}`,
+	} {
+		in, err := ordered.DecodeJSON(json.NewDecoder(strings.NewReader(s)))
+		require.NoError(t, err)
+		o := ordered.NewOrders()
+		b, err := json.Marshal(o.Restore(o.Plain(in)))
+		require.NoError(t, err)
+		require.Equal(t, s, string(b))
+	}
+}
@@ -0,0 +1,239 @@
+// Copyright 2026 The gutenfmt authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ordered provides a map, which preserves the insertion order of its
+// keys when being formatted, e.g., as JSON, YAML or table.
+package ordered
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Map is a map with string keys, which remembers the order of insertion.
+//
+// The zero value is an empty map ready to use.
+// Methods, which do not modify the Map, have value receivers so that both,
+// Map and *Map, can be passed to a Writer.
+type Map struct {
+	keys []string
+	vals map[string]any
+}
+
+// NewMap creates a new empty Map with the given capacity.
+func NewMap(capacity int) *Map {
+	return &Map{make([]string, 0, capacity), make(map[string]any, capacity)}
+}
+
+// FromMap creates a new Map from a regular map with keys sorted in lexical order.
+func FromMap(m map[string]any) *Map {
+	om := NewMap(len(m))
+	for _, k := range SortedKeys(m) {
+		om.Set(k, m[k])
+	}
+	return om
+}
+
+// Set sets the value for a key.
+// New keys are appended, whereas existing keys keep their position.
+func (m *Map) Set(k string, v any) {
+	if m.vals == nil {
+		m.vals = make(map[string]any)
+	}
+	if _, ok := m.vals[k]; !ok {
+		m.keys = append(m.keys, k)
+	}
+	m.vals[k] = v
+}
+
+// Get returns the value for a key and whether the key exists.
+func (m Map) Get(k string) (any, bool) {
+	v, ok := m.vals[k]
+	return v, ok
+}
+
+// Delete removes a key and its value, if present.
+func (m *Map) Delete(k string) {
+	if _, ok := m.vals[k]; !ok {
+		return
+	}
+	delete(m.vals, k)
+	for idx, e := range m.keys {
+		if e == k {
+			m.keys = append(m.keys[:idx], m.keys[idx+1:]...)
+			break
+		}
+	}
+}
+
+// Keys returns a copy of the keys in order.
+func (m Map) Keys() []string {
+	return append([]string(nil), m.keys...)
+}
+
+// Len returns the number of entries.
+func (m Map) Len() int {
+	return len(m.keys)
+}
+
+// ToMap returns a regular map with the same entries (shallow copy).
+func (m Map) ToMap() map[string]any {
+	r := make(map[string]any, len(m.keys))
+	for k, v := range m.vals {
+		r[k] = v
+	}
+	return r
+}
+
+// String returns the entries in the same format as fmt.Sprint for maps,
+// but in order.
+func (m Map) String() string {
+	b := &strings.Builder{}
+	b.WriteString("map[")
+	for idx, k := range m.keys {
+		if idx > 0 {
+			b.WriteByte(' ')
+		}
+		_, _ = fmt.Fprintf(b, "%v:%v", k, m.vals[k])
+	}
+	b.WriteByte(']')
+	return b.String()
+}
+
+// MarshalJSON encodes the Map as JSON object with keys in order.
+func (m Map) MarshalJSON() ([]byte, error) {
+	b := &bytes.Buffer{}
+	b.WriteByte('{')
+	for idx, k := range m.keys {
+		if idx > 0 {
+			b.WriteByte(',')
+		}
+		kj, err := json.Marshal(k)
+		if err != nil {
+			return nil, err
+		}
+		vj, err := json.Marshal(m.vals[k])
+		if err != nil {
+			return nil, err
+		}
+		b.Write(kj)
+		b.WriteByte(':')
+		b.Write(vj)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+// UnmarshalJSON decodes a JSON object.
+// Nested objects are decoded as *Map as well.
+func (m *Map) UnmarshalJSON(b []byte) error {
+	d := json.NewDecoder(bytes.NewReader(b))
+	v, err := DecodeJSON(d)
+	if err != nil {
+		return err
+	}
+	om, ok := v.(*Map)
+	if !ok {
+		return fmt.Errorf("cannot unmarshal %T into ordered.Map", v)
+	}
+	*m = *om
+	return nil
+}
+
+// MarshalYAML encodes the Map as YAML mapping with keys in order.
+func (m Map) MarshalYAML() (any, error) {
+	n := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	for _, k := range m.keys {
+		kn, vn := &yaml.Node{}, &yaml.Node{}
+		if err := kn.Encode(k); err != nil {
+			return nil, err
+		}
+		if err := vn.Encode(m.vals[k]); err != nil {
+			return nil, err
+		}
+		n.Content = append(n.Content, kn, vn)
+	}
+	return n, nil
+}
+
+// DecodeJSON reads the next JSON value from d.
+// Objects are decoded as *Map, all other values like json.Unmarshal does.
+// If there is no further value, io.EOF is returned.
+func DecodeJSON(d *json.Decoder) (any, error) {
+	t, err := d.Token()
+	if err != nil {
+		return nil, err
+	}
+	return decodeJSONToken(d, t)
+}
+
+// decodeJSONToken decodes the value, which starts with the given token.
+func decodeJSONToken(d *json.Decoder, t json.Token) (any, error) {
+	switch t {
+	case json.Delim('{'):
+		m := NewMap(0)
+		for d.More() {
+			kt, err := d.Token()
+			if err != nil {
+				return nil, err
+			}
+			v, err := decodeJSONValue(d)
+			if err != nil {
+				return nil, err
+			}
+			m.Set(kt.(string), v)
+		}
+		_, err := d.Token()
+		return m, err
+	case json.Delim('['):
+		s := []any{}
+		for d.More() {
+			v, err := decodeJSONValue(d)
+			if err != nil {
+				return nil, err
+			}
+			s = append(s, v)
+		}
+		_, err := d.Token()
+		return s, err
+	default:
+		return t, nil
+	}
+}
+
+// decodeJSONValue decodes a nested value, where io.EOF is unexpected.
+func decodeJSONValue(d *json.Decoder) (any, error) {
+	v, err := DecodeJSON(d)
+	if errors.Is(err, io.EOF) {
+		return nil, io.ErrUnexpectedEOF
+	}
+	return v, err
+}
+
+// SortedKeys returns the keys of m in lexical order.
+func SortedKeys[V any](m map[string]V) []string {
+	ks := make([]string, 0, len(m))
+	for k := range m {
+		ks = append(ks, k)
+	}
+	sort.Strings(ks)
+	return ks
+}
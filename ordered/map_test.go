@@ -0,0 +1,97 @@
+// Copyright 2026 The gutenfmt authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ordered_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/abc-inc/gutenfmt/ordered"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestMap(t *testing.T) {
+	m := ordered.NewMap(0)
+	m.Set("b", 1)
+	m.Set("a", 2)
+	m.Set("c", 3)
+	m.Set("b", 4)
+	require.Equal(t, []string{"b", "a", "c"}, m.Keys())
+	require.Equal(t, "map[b:4 a:2 c:3]", m.String())
+
+	m.Delete("a")
+	m.Delete("x")
+	require.Equal(t, []string{"b", "c"}, m.Keys())
+	require.Equal(t, map[string]any{"b": 4, "c": 3}, m.ToMap())
+	v, ok := m.Get("b")
+	require.True(t, ok)
+	require.Equal(t, 4, v)
+
+	var z ordered.Map
+	_, ok = z.Get("x")
+	require.False(t, ok)
+	z.Set("x", nil)
+	require.Equal(t, 1, z.Len())
+}
+
+func TestFromMap(t *testing.T) {
+	m := ordered.FromMap(map[string]any{"b": 1, "a": 2})
+	require.Equal(t, []string{"a", "b"}, m.Keys())
+}
+
+func TestMap_JSON(t *testing.T) {
+	in := `{"z":1,"a":{"y":[{"c":1,"b":2}],"x":null},"m":"s"}`
+	m := &ordered.Map{}
+	require.NoError(t, json.Unmarshal([]byte(in), m))
+	require.Equal(t, []string{"z", "a", "m"}, m.Keys())
+
+	b, err := json.Marshal(m)
+	require.NoError(t, err)
+	require.Equal(t, in, string(b))
+
+	require.Error(t, json.Unmarshal([]byte("[]"), m))
+	require.Error(t, json.Unmarshal([]byte(`{"a":}`), m))
+}
+
+func TestMap_YAML(t *testing.T) {
+	m := ordered.NewMap(2)
+	m.Set("z", []any{1, 2})
+	n := ordered.NewMap(1)
+	n.Set("y", "x")
+	n.Set("b", true)
+	m.Set("a", n)
+
+	b, err := yaml.Marshal(m)
+	require.NoError(t, err)
+	require.Equal(t, "z:\n    - 1\n    - 2\na:\n    \"y\": x\n    b: true\n", string(b))
+}
+
+func TestDecodeJSON(t *testing.T) {
+	d := json.NewDecoder(strings.NewReader(`{"b":1} [2] "s"`))
+	v, err := ordered.DecodeJSON(d)
+	require.NoError(t, err)
+	require.Equal(t, []string{"b"}, v.(*ordered.Map).Keys())
+	v, err = ordered.DecodeJSON(d)
+	require.NoError(t, err)
+	require.Equal(t, []any{2.0}, v)
+	v, err = ordered.DecodeJSON(d)
+	require.NoError(t, err)
+	require.Equal(t, "s", v)
+
+	_, err = ordered.DecodeJSON(json.NewDecoder(strings.NewReader(`{"b":`)))
+	require.Error(t, err)
+}